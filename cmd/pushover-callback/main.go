@@ -0,0 +1,100 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+// Command pushover-callback receives Pushover acknowledgement callbacks and
+// writes each one to standard output as a line of JSON, for a log shipper or
+// another process to consume.
+//
+// Set the callback URL of emergency messages to the listening address and
+// path, and restrict the accepted sources with -allow:
+//
+//	pushover-callback -addr :8080 -path /pushover/callback -allow 203.0.113.0/24
+//
+// At least one -allow is required. Pass -allow-any instead to accept callbacks
+// from any address, for example when a proxy in front already restricts them.
+//
+// Redelivered callbacks for the same receipt are written once.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"net/netip"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/callback"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	path := flag.String("path", "/", "path to receive callbacks on")
+	forwarded := flag.String("forwarded-header", "", "take the source address from this `header`, e.g. X-Forwarded-For, when behind a proxy")
+	dedup := flag.Duration("dedup", callback.DefaultDedupTTL, "how long to ignore redelivered callbacks for a receipt")
+	var allowed []netip.Prefix
+	flag.Func("allow", "only accept callbacks from this `CIDR` (repeatable)", func(v string) error {
+		p, err := netip.ParsePrefix(v)
+		if err != nil {
+			return err
+		}
+		allowed = append(allowed, p)
+		return nil
+	})
+	allowAny := flag.Bool("allow-any", false, "accept callbacks from any address when no -allow is given")
+	flag.Parse()
+
+	if err := checkSources(allowed, *allowAny); err != nil {
+		log.Fatal(err)
+	}
+	if *allowAny && len(allowed) == 0 {
+		log.Print("warning: accepting callbacks from any address; anyone who can reach this service can report acknowledgements")
+	}
+
+	opts := []callback.Option{callback.WithDedupTTL(*dedup), callback.OnEvent(writeEvents(os.Stdout))}
+	if len(allowed) > 0 {
+		opts = append(opts, callback.WithAllowedSources(allowed...))
+	}
+	if *forwarded != "" {
+		opts = append(opts, callback.WithForwardedHeader(*forwarded))
+	}
+
+	log.Printf("receiving Pushover callbacks on %s%s", *addr, *path)
+	srv := &http.Server{Addr: *addr, Handler: newHandler(*path, callback.New(opts...)), ReadHeaderTimeout: 10 * time.Second}
+	log.Fatal(srv.ListenAndServe())
+}
+
+// checkSources refuses to start without a source allowlist unless allowAny
+// says that accepting every address is intended.
+func checkSources(allowed []netip.Prefix, allowAny bool) error {
+	if len(allowed) == 0 && !allowAny {
+		return errors.New("no -allow given: pass -allow with Pushover's source addresses, or -allow-any to accept callbacks from anywhere")
+	}
+	return nil
+}
+
+// newHandler serves h on path and a health check on /healthz.
+func newHandler(path string, h http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(path, h)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok\n")
+	})
+	return mux
+}
+
+// writeEvents returns an event function that writes events to w as JSON lines.
+func writeEvents(w io.Writer) func(context.Context, callback.Event) error {
+	enc := json.NewEncoder(w)
+	var mu sync.Mutex
+	return func(_ context.Context, ev callback.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return enc.Encode(ev)
+	}
+}
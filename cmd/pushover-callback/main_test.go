@@ -0,0 +1,77 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/callback"
+)
+
+func TestCallback_WritesJSONLines(t *testing.T) {
+	var out bytes.Buffer
+	srv := httptest.NewServer(newHandler("/pushover/callback", callback.New(callback.OnEvent(writeEvents(&out)))))
+	t.Cleanup(srv.Close)
+
+	form := url.Values{
+		"receipt":         {"rTestReceipt00000000000000001x"},
+		"acknowledged":    {"1"},
+		"acknowledged_at": {"1700000000"},
+		"acknowledged_by": {"uTestUser000000000000000000001"},
+	}
+	for i := 0; i < 2; i++ {
+		resp, err := http.PostForm(srv.URL+"/pushover/callback", form)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d", resp.StatusCode)
+		}
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	if len(lines) != 1 {
+		t.Fatalf("wrote %d lines, want 1 (redelivery ignored):\n%s", len(lines), out.String())
+	}
+	var ev callback.Event
+	if err := json.Unmarshal(lines[0], &ev); err != nil {
+		t.Fatal(err)
+	}
+	if ev.Receipt != form.Get("receipt") || !ev.Acknowledged || ev.AcknowledgedBy != form.Get("acknowledged_by") {
+		t.Errorf("event = %+v", ev)
+	}
+}
+
+func TestCallback_Healthz(t *testing.T) {
+	srv := httptest.NewServer(newHandler("/pushover/callback", callback.New()))
+	t.Cleanup(srv.Close)
+	resp, err := http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d", resp.StatusCode)
+	}
+}
+
+func TestCheckSources(t *testing.T) {
+	allowed := []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")}
+	if err := checkSources(nil, false); err == nil {
+		t.Error("expected an error without -allow or -allow-any")
+	}
+	if err := checkSources(nil, true); err != nil {
+		t.Errorf("-allow-any: unexpected error: %v", err)
+	}
+	if err := checkSources(allowed, false); err != nil {
+		t.Errorf("-allow: unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
+)
+
+// groupView is the JSON form of a group.
+type groupView struct {
+	Key     string                 `json:"key"`
+	Name    string                 `json:"name"`
+	Members []pushover.GroupMember `json:"members"`
+}
+
+// newHandler serves the fake API under /1/ and the inspection and control
+// endpoints under /_mock/. Control endpoints take form values and answer
+// with JSON, or redirect to the message log when submitted from it.
+func newHandler(f *pushovertest.Fake) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/1/", f)
+	mux.Handle("GET /{$}", http.RedirectHandler("/_mock/", http.StatusFound))
+	mux.HandleFunc("GET /_mock/{$}", func(w http.ResponseWriter, _ *http.Request) {
+		renderPage(w, f)
+	})
+
+	mux.HandleFunc("GET /_mock/messages", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, f.Messages())
+	})
+	mux.HandleFunc("GET /_mock/receipts", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, f.Receipts())
+	})
+	mux.HandleFunc("POST /_mock/receipts/{receipt}/acknowledge", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("receipt")
+		user := r.FormValue("user")
+		if user == "" {
+			if rec, err := f.Receipt(id); err == nil && len(rec.Users) > 0 {
+				user = rec.Users[0]
+			}
+		}
+		done(w, r, f.Acknowledge(id, user, r.FormValue("device")))
+	})
+	mux.HandleFunc("POST /_mock/receipts/{receipt}/expire", func(w http.ResponseWriter, r *http.Request) {
+		done(w, r, f.Expire(r.PathValue("receipt")))
+	})
+
+	mux.HandleFunc("GET /_mock/groups", func(w http.ResponseWriter, _ *http.Request) {
+		views := []groupView{}
+		for _, key := range f.GroupKeys() {
+			name, members, _ := f.Group(key)
+			views = append(views, groupView{Key: key, Name: name, Members: members})
+		}
+		writeJSON(w, http.StatusOK, views)
+	})
+	mux.HandleFunc("POST /_mock/groups/{group}", func(w http.ResponseWriter, r *http.Request) {
+		key := r.PathValue("group")
+		if err := pushover.ValidateKey("group", key); err != nil {
+			done(w, r, err)
+			return
+		}
+		f.AddGroup(key, r.FormValue("name"))
+		done(w, r, nil)
+	})
+	mux.HandleFunc("POST /_mock/groups/{group}/members", func(w http.ResponseWriter, r *http.Request) {
+		disabled, _ := strconv.ParseBool(r.FormValue("disabled"))
+		done(w, r, f.AddGroupMember(r.PathValue("group"), pushover.GroupMember{
+			User:     r.FormValue("user"),
+			Device:   r.FormValue("device"),
+			Memo:     r.FormValue("memo"),
+			Disabled: disabled,
+		}))
+	})
+	mux.HandleFunc("POST /_mock/groups/{group}/members/remove", func(w http.ResponseWriter, r *http.Request) {
+		done(w, r, f.RemoveGroupMember(r.PathValue("group"), r.FormValue("user"), r.FormValue("device")))
+	})
+
+	mux.HandleFunc("POST /_mock/users/{user}", func(w http.ResponseWriter, r *http.Request) {
+		key := r.PathValue("user")
+		if err := pushover.ValidateKey("user", key); err != nil {
+			done(w, r, err)
+			return
+		}
+		var devices []string
+		if d := r.FormValue("devices"); d != "" {
+			devices = strings.Split(d, ",")
+		}
+		f.AddUser(key, devices...)
+		done(w, r, nil)
+	})
+	mux.HandleFunc("POST /_mock/fail", func(w http.ResponseWriter, r *http.Request) {
+		status, err := strconv.Atoi(r.FormValue("status"))
+		if err != nil || status < 400 || status > 599 {
+			done(w, r, errors.New("status must be an HTTP error status"))
+			return
+		}
+		var errs []string
+		if e := r.FormValue("error"); e != "" {
+			errs = append(errs, e)
+		}
+		f.FailNext(r.FormValue("endpoint"), status, errs...)
+		done(w, r, nil)
+	})
+	return mux
+}
+
+// done answers a control request: a redirect back to the message log for
+// forms submitted from it, JSON otherwise.
+func done(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusOK
+	switch {
+	case errors.Is(err, pushovertest.ErrNotFound):
+		status = http.StatusNotFound
+	case err != nil:
+		status = http.StatusBadRequest
+	}
+	if err == nil && r.FormValue("return") != "" {
+		http.Redirect(w, r, "/_mock/", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, status, map[string]bool{"ok": true})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,101 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+// Command pushover-mock serves a fake Pushover API on a local port, so that
+// Terraform modules can be applied without sending real notifications.
+//
+// Point the provider at it with PUSHOVER_BASE_URL and use the printed
+// credentials, or register your own with -token, -user and -group:
+//
+//	pushover-mock -addr 127.0.0.1:8080 -user uQiRzpo4DXghDmr9QzzfQu27cmVRsG:iphone
+//
+// Accepted messages and receipts are listed at /_mock/, with buttons to
+// acknowledge or expire receipts. The same state is available as JSON, and
+// can be changed, through the /_mock/ endpoints described in the README.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8080", "address to listen on")
+	var tokens, users, groups []string
+	flag.Func("token", "additional application `token` to accept (repeatable)", appendTo(&tokens))
+	flag.Func("user", "additional user, as `key[:device,device]` (repeatable)", appendTo(&users))
+	flag.Func("group", "additional empty group, as `key[:name]` (repeatable)", appendTo(&groups))
+	flag.Parse()
+
+	fake := pushovertest.NewFake()
+	if err := register(fake, tokens, users, groups); err != nil {
+		log.Fatal(err)
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	base := "http://" + ln.Addr().String()
+	fmt.Printf(`Fake Pushover API listening on %[1]s/1
+Message log: %[1]s/_mock/
+
+export PUSHOVER_BASE_URL=%[1]s/1
+export PUSHOVER_API_TOKEN=%[2]s
+export PUSHOVER_USER_KEY=%[3]s   # devices: %[5]s
+export PUSHOVER_GROUP_KEY=%[4]s
+`, base, pushovertest.Token, pushovertest.UserKey, pushovertest.GroupKey, strings.Join(pushovertest.UserDevices, ", "))
+
+	srv := &http.Server{Handler: newHandler(fake), ReadHeaderTimeout: 10 * time.Second}
+	log.Fatal(srv.Serve(ln))
+}
+
+func appendTo(list *[]string) func(string) error {
+	return func(v string) error {
+		*list = append(*list, v)
+		return nil
+	}
+}
+
+// register adds the applications, users and groups given on the command line.
+func register(f *pushovertest.Fake, tokens, users, groups []string) error {
+	for _, t := range tokens {
+		if err := pushover.ValidateKey("token", t); err != nil {
+			return err
+		}
+		f.AddApplication(t)
+	}
+	for _, u := range users {
+		key, devices, _ := strings.Cut(u, ":")
+		if err := pushover.ValidateKey("user", key); err != nil {
+			return err
+		}
+		var names []string
+		if devices != "" {
+			names = strings.Split(devices, ",")
+		}
+		f.AddUser(key, names...)
+	}
+	for _, g := range groups {
+		key, name, _ := strings.Cut(g, ":")
+		if err := pushover.ValidateKey("group", key); err != nil {
+			return err
+		}
+		if name == "" {
+			name = key
+		}
+		f.AddGroup(key, name)
+	}
+	if len(tokens)+len(users)+len(groups) > 0 {
+		log.Printf("registered %d token(s), %d user(s), %d group(s)", len(tokens), len(users), len(groups))
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *pushover.Client) {
+	t.Helper()
+	srv := httptest.NewServer(newHandler(pushovertest.NewFake()))
+	t.Cleanup(srv.Close)
+	return srv, pushover.New(pushovertest.Token, pushover.WithBaseURL(srv.URL+"/1"))
+}
+
+func postForm(t *testing.T, rawURL string, form url.Values) *http.Response {
+	t.Helper()
+	resp, err := http.PostForm(rawURL, form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestMock_AcknowledgeReceipt(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+	sent, err := client.SendMessage(ctx, &pushover.MessageRequest{
+		User: pushovertest.UserKey, Message: "disk full", Priority: 2, Retry: 30, Expire: 600,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := postForm(t, srv.URL+"/_mock/receipts/"+sent.Receipt+"/acknowledge", url.Values{"device": {"iphone"}})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("acknowledge returned %d", resp.StatusCode)
+	}
+	receipt, err := client.GetReceipt(ctx, sent.Receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Acknowledged != 1 || receipt.AcknowledgedBy != pushovertest.UserKey || receipt.AcknowledgedByDevice != "iphone" {
+		t.Errorf("unexpected receipt: %+v", receipt)
+	}
+
+	if resp := postForm(t, srv.URL+"/_mock/receipts/rUnknown/acknowledge", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown receipt returned %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestMock_MessageLog(t *testing.T) {
+	srv, client := newTestServer(t)
+	if _, err := client.SendMessage(context.Background(), &pushover.MessageRequest{User: pushovertest.UserKey, Message: "<b>deployed</b>"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get(srv.URL + "/_mock/messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var messages []pushovertest.Message
+	if err := json.NewDecoder(resp.Body).Decode(&messages); err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 || messages[0].Message != "<b>deployed</b>" {
+		t.Fatalf("unexpected messages: %+v", messages)
+	}
+
+	resp, err = http.Get(srv.URL + "/_mock/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	html, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(html), "&lt;b&gt;deployed&lt;/b&gt;") {
+		t.Errorf("expected the escaped message in the page, got:\n%s", html)
+	}
+}
+
+func TestMock_GroupMembers(t *testing.T) {
+	srv, client := newTestServer(t)
+	members := srv.URL + "/_mock/groups/" + pushovertest.GroupKey + "/members"
+
+	postForm(t, members, url.Values{"user": {pushovertest.OtherUserKey}, "memo": {"added by hand"}})
+	group, err := client.GetGroup(context.Background(), pushovertest.GroupKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(group.Users) != 1 || group.Users[0].Memo != "added by hand" {
+		t.Fatalf("unexpected group: %+v", group)
+	}
+
+	resp := postForm(t, members+"/remove", url.Values{"user": {pushovertest.OtherUserKey}, "return": {"1"}})
+	if resp.Request.URL.Path != "/_mock/" {
+		t.Errorf("expected a redirect to the message log, ended at %s", resp.Request.URL.Path)
+	}
+	if group, _ := client.GetGroup(context.Background(), pushovertest.GroupKey); len(group.Users) != 0 {
+		t.Errorf("expected an empty group, got %+v", group.Users)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	f := pushovertest.NewFake()
+	if err := register(f, nil, []string{"uQiRzpo4DXghDmr9QzzfQu27cmVRsG:iphone,ipad"}, []string{"gznej3rKEVAvPUxu9vvNnqpmZpokzF:Ops"}); err != nil {
+		t.Fatal(err)
+	}
+	if name, _, err := f.Group("gznej3rKEVAvPUxu9vvNnqpmZpokzF"); err != nil || name != "Ops" {
+		t.Errorf("group not registered: %q, %v", name, err)
+	}
+	if err := register(f, []string{"short"}, nil, nil); err == nil {
+		t.Error("expected a malformed token to be rejected")
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
+)
+
+var page = template.Must(template.New("page").Funcs(template.FuncMap{
+	"time": func(t time.Time) string { return t.Local().Format("15:04:05") },
+	"expired": func(r pushovertest.Receipt) bool {
+		return r.Canceled || r.Expired(time.Now())
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="5">
+<title>pushover-mock</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+code { font-size: 0.9em; }
+form { display: inline; }
+</style>
+</head>
+<body>
+<h1>pushover-mock</h1>
+<p>JSON: <a href="/_mock/messages">messages</a> · <a href="/_mock/receipts">receipts</a> · <a href="/_mock/groups">groups</a></p>
+
+<h2>Messages</h2>
+{{if .Messages}}
+<table>
+<tr><th>Sent</th><th>Recipients</th><th>Priority</th><th>Title</th><th>Message</th><th>Receipt</th></tr>
+{{range .Messages}}
+<tr>
+<td>{{time .SentAt}}</td>
+<td>{{range .Users}}<code>{{.}}</code><br>{{end}}{{if .Device}}device: {{.Device}}{{end}}</td>
+<td>{{.Priority}}</td>
+<td>{{.Title}}</td>
+<td>{{.Message}}</td>
+<td>{{if .Receipt}}<code>{{.Receipt}}</code>{{end}}</td>
+</tr>
+{{end}}
+</table>
+{{else}}
+<p>No messages yet.</p>
+{{end}}
+
+<h2>Receipts</h2>
+{{if .Receipts}}
+<table>
+<tr><th>Receipt</th><th>Status</th><th>Expires</th><th></th></tr>
+{{range .Receipts}}
+<tr>
+<td><code>{{.Receipt}}</code></td>
+<td>{{if .Acknowledged}}acknowledged by <code>{{.AcknowledgedBy}}</code>{{else if .Canceled}}canceled{{else if expired .}}expired{{else}}retrying{{end}}</td>
+<td>{{time .ExpiresAt}}</td>
+<td>{{if not (or .Acknowledged (expired .))}}
+<form method="post" action="/_mock/receipts/{{.Receipt}}/acknowledge"><input type="hidden" name="return" value="1"><button>Acknowledge</button></form>
+<form method="post" action="/_mock/receipts/{{.Receipt}}/expire"><input type="hidden" name="return" value="1"><button>Expire</button></form>
+{{end}}</td>
+</tr>
+{{end}}
+</table>
+{{else}}
+<p>No emergency receipts yet.</p>
+{{end}}
+
+<h2>Groups</h2>
+{{range .Groups}}
+<h3>{{.Name}} <code>{{.Key}}</code></h3>
+<table>
+<tr><th>User</th><th>Device</th><th>Memo</th><th>Status</th><th></th></tr>
+{{$key := .Key}}
+{{range .Members}}
+<tr>
+<td><code>{{.User}}</code></td><td>{{.Device}}</td><td>{{.Memo}}</td><td>{{if .Disabled}}disabled{{else}}active{{end}}</td>
+<td><form method="post" action="/_mock/groups/{{$key}}/members/remove"><input type="hidden" name="return" value="1"><input type="hidden" name="user" value="{{.User}}"><input type="hidden" name="device" value="{{.Device}}"><button>Remove</button></form></td>
+</tr>
+{{end}}
+<tr>
+<td colspan="5"><form method="post" action="/_mock/groups/{{$key}}/members"><input type="hidden" name="return" value="1">
+<input name="user" placeholder="user key" size="32"> <input name="device" placeholder="device"> <input name="memo" placeholder="memo"> <button>Add member</button></form></td>
+</tr>
+</table>
+{{end}}
+</body>
+</html>
+`))
+
+// renderPage writes the HTML message log, newest message first.
+func renderPage(w http.ResponseWriter, f *pushovertest.Fake) {
+	messages := f.Messages()
+	slices.Reverse(messages)
+	var groups []groupView
+	for _, key := range f.GroupKeys() {
+		name, members, _ := f.Group(key)
+		groups = append(groups, groupView{Key: key, Name: name, Members: members})
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := page.Execute(w, struct {
+		Messages []pushovertest.Message
+		Receipts []pushovertest.Receipt
+		Groups   []groupView
+	}{messages, f.Receipts(), groups})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,40 @@
+terraform {
+  required_providers {
+    pushover = {
+      source  = "Josh-Archer/pushover"
+      version = "~> 1.0"
+    }
+  }
+}
+
+provider "pushover" {
+  api_token = var.pushover_api_token
+}
+
+variable "pushover_api_token" {
+  type      = string
+  sensitive = true
+}
+
+variable "pushover_group_key" {
+  type      = string
+  sensitive = true
+}
+
+# Validate every member of the on-call group.
+data "pushover_group_health" "on_call" {
+  group_key = var.pushover_group_key
+}
+
+# Warn on every plan when the roster has gone stale.
+check "on_call_roster" {
+  assert {
+    condition     = data.pushover_group_health.on_call.healthy
+    error_message = "On-call group has invalid members (${join(", ", data.pushover_group_health.on_call.invalid_members)}), disabled members (${join(", ", data.pushover_group_health.on_call.disabled_members)}) or members bound to missing devices (${join(", ", data.pushover_group_health.on_call.missing_device_members)})."
+  }
+}
+
+output "member_licenses" {
+  description = "Active Pushover licenses for each group member."
+  value       = { for m in data.pushover_group_health.on_call.members : m.id => m.licenses }
+}
@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    pushover = {
+      source  = "Josh-Archer/pushover"
+      version = "~> 1.0"
+    }
+  }
+}
+
+provider "pushover" {
+  api_token = var.pushover_api_token
+}
+
+variable "pushover_api_token" {
+  type      = string
+  sensitive = true
+}
+
+# Read the license credits left to assign.
+data "pushover_license_credits" "available" {}
+
+# Warn on every plan when the balance runs low.
+check "license_credits" {
+  assert {
+    condition     = data.pushover_license_credits.available.credits >= 5
+    error_message = "Only ${data.pushover_license_credits.available.credits} Pushover license credits left."
+  }
+}
+
+output "license_credits" {
+  value = data.pushover_license_credits.available.credits
+}
@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    pushover = {
+      source  = "Josh-Archer/pushover"
+      version = "~> 1.0"
+    }
+  }
+}
+
+provider "pushover" {
+  applications = {
+    team = { api_token = var.pushover_team_token }
+  }
+}
+
+variable "pushover_team_token" {
+  type      = string
+  sensitive = true
+}
+
+# List every member of the team.
+data "pushover_team" "ops" {
+  application = "team"
+}
+
+output "team_members" {
+  description = "User key of each team member, by email address."
+  value       = { for m in data.pushover_team.ops.members : m.email => m.user_key }
+}
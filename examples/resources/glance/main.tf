@@ -0,0 +1,54 @@
+# --- Variables ---
+variable "pushover_api_token" {
+  description = "Pushover application API token."
+  type        = string
+  sensitive   = true
+}
+
+variable "pushover_user_key" {
+  description = "Pushover user key whose devices show the glance."
+  type        = string
+  sensitive   = true
+}
+
+variable "deploy_count" {
+  description = "Number of deployments today."
+  type        = number
+  default     = 0
+}
+
+variable "error_budget_remaining" {
+  description = "Remaining error budget, in percent."
+  type        = number
+  default     = 100
+}
+
+# --- Provider ---
+terraform {
+  required_providers {
+    pushover = {
+      source  = "Josh-Archer/pushover"
+      version = "~> 1.0"
+    }
+  }
+}
+
+provider "pushover" {
+  api_token = var.pushover_api_token
+}
+
+# --- Example 1: Deployment count on every device ---
+resource "pushover_glance" "deploys" {
+  user_key    = var.pushover_user_key
+  title       = "Deploys today"
+  count_value = var.deploy_count
+}
+
+# --- Example 2: Error budget on a single watch ---
+resource "pushover_glance" "error_budget" {
+  user_key = var.pushover_user_key
+  device   = "watch"
+  title    = "Error budget"
+  text     = "${var.error_budget_remaining}% remaining"
+  percent  = var.error_budget_remaining
+}
@@ -0,0 +1,54 @@
+# --- Variables ---
+variable "pushover_api_token" {
+  description = "API token of an application owned by the account holding the license credits."
+  type        = string
+  sensitive   = true
+}
+
+variable "on_call_group_key" {
+  description = "Delivery group that receives on-call alerts."
+  type        = string
+}
+
+# --- Provider ---
+terraform {
+  required_providers {
+    pushover = {
+      source  = "Josh-Archer/pushover"
+      version = "~> 1.0"
+    }
+  }
+}
+
+provider "pushover" {
+  api_token = var.pushover_api_token
+}
+
+# --- Example 1: License a new hire's phone and add them to the on-call group ---
+variable "new_hire_user_key" {
+  description = "User key of the new hire."
+  type        = string
+}
+
+resource "pushover_license_assignment" "new_hire" {
+  user_key = var.new_hire_user_key
+  os       = "iOS"
+}
+
+resource "pushover_group_user" "new_hire" {
+  group_key = var.on_call_group_key
+  user_key  = pushover_license_assignment.new_hire.user_key
+  memo      = "New hire"
+}
+
+# --- Example 2: License people by email before they have an account ---
+variable "invitees" {
+  description = "Email addresses to license."
+  type        = set(string)
+  default     = ["ada@example.com", "bob@example.com"]
+}
+
+resource "pushover_license_assignment" "invitees" {
+  for_each = var.invitees
+  email    = each.value
+}
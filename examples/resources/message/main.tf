@@ -77,3 +77,16 @@ resource "pushover_message" "device_specific" {
   message  = "This notification goes only to your iPhone."
   device   = "iphone"
 }
+
+# --- Example 6: Several recipients in as few API calls as possible ---
+variable "release_watchers" {
+  description = "Map of person to Pushover user key."
+  type        = map(string)
+  default     = {}
+}
+
+resource "pushover_message" "release" {
+  count     = length(var.release_watchers) > 0 ? 1 : 0
+  user_keys = toset(values(var.release_watchers))
+  message   = "Release 4.2.0 is live."
+}
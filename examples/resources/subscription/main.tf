@@ -0,0 +1,58 @@
+# --- Variables ---
+variable "pushover_api_token" {
+  description = "API token of the application that owns the subscription."
+  type        = string
+  sensitive   = true
+}
+
+variable "subscription_code" {
+  description = "Subscription code from the Pushover dashboard."
+  type        = string
+}
+
+# --- Provider ---
+terraform {
+  required_providers {
+    pushover = {
+      source  = "Josh-Archer/pushover"
+      version = "~> 1.0"
+    }
+  }
+}
+
+provider "pushover" {
+  api_token = var.pushover_api_token
+}
+
+# --- Example 1: Subscribe a user and welcome them ---
+resource "pushover_subscription_user" "alice" {
+  user_key          = "uAliceUserKey"
+  subscription_code = var.subscription_code
+}
+
+resource "pushover_message" "welcome" {
+  user_key = pushover_subscription_user.alice.subscribed_user_key
+  message  = "You are now subscribed to ops alerts"
+}
+
+# --- Example 2: Subscribe a roster, each on one device with a chosen sound ---
+variable "subscribers" {
+  description = "Map of name to user key, device and sound."
+  type = map(object({
+    user_key = string
+    device   = string
+    sound    = string
+  }))
+  default = {
+    bob   = { user_key = "uBobKey",   device = "iphone", sound = "siren" }
+    carol = { user_key = "uCarolKey", device = "pixel",  sound = "bugle" }
+  }
+}
+
+resource "pushover_subscription_user" "roster" {
+  for_each          = var.subscribers
+  user_key          = each.value.user_key
+  subscription_code = var.subscription_code
+  device            = each.value.device
+  sound             = each.value.sound
+}
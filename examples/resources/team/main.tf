@@ -0,0 +1,65 @@
+# --- Variables ---
+variable "pushover_api_token" {
+  description = "API token of the application that sends notifications."
+  type        = string
+  sensitive   = true
+}
+
+variable "pushover_team_token" {
+  description = "API token of the Pushover for Teams team, from the team's settings."
+  type        = string
+  sensitive   = true
+}
+
+variable "on_call_group_key" {
+  description = "Delivery group that receives on-call alerts."
+  type        = string
+}
+
+# --- Provider ---
+terraform {
+  required_providers {
+    pushover = {
+      source  = "Josh-Archer/pushover"
+      version = "~> 1.0"
+    }
+  }
+}
+
+provider "pushover" {
+  api_token = var.pushover_api_token
+  applications = {
+    team = { api_token = var.pushover_team_token }
+  }
+}
+
+# --- Example 1: Onboard a roster into the team and the on-call group ---
+variable "on_call" {
+  description = "Map of email address to display name."
+  type        = map(string)
+  default = {
+    "ada@example.com" = "Ada Lovelace"
+    "bob@example.com" = "Bob Barker"
+  }
+}
+
+resource "pushover_team_member" "on_call" {
+  for_each    = var.on_call
+  email       = each.key
+  name        = each.value
+  group_key   = var.on_call_group_key
+  application = "team"
+}
+
+# --- Example 2: Create an account immediately and manage its group membership ---
+resource "pushover_team_member" "contractor" {
+  email       = "carol@example.com"
+  instant     = true
+  application = "team"
+}
+
+resource "pushover_group_user" "contractor" {
+  group_key = var.on_call_group_key
+  user_key  = pushover_team_member.contractor.user_key
+  memo      = "Contractor"
+}
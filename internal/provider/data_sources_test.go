@@ -4,19 +4,23 @@
 package provider_test
 
 import (
+"net/http"
 "os"
+"regexp"
 "testing"
 
 "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
 )
 
-// ----- pushover_sounds (acceptance) -----
+// ----- pushover_sounds (fake API unless TF_ACC is set) -----
 
 // TestSoundsDataSource_ReturnsMap validates the data source returns a map of sounds.
-// Requires PUSHOVER_API_TOKEN to be set.
 func TestSoundsDataSource_ReturnsMap(t *testing.T) {
-skipIfNoToken(t)
-resource.Test(t, resource.TestCase{
+testAPI(t)
+resource.UnitTest(t, resource.TestCase{
 ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 Steps: []resource.TestStep{
 {
@@ -32,17 +36,16 @@ resource.TestCheckResourceAttrSet("data.pushover_sounds.all", "sounds.pushover")
 })
 }
 
-// ----- pushover_validate_user (acceptance) -----
+// ----- pushover_validate_user (fake API unless TF_ACC is set) -----
 
-// TestValidateUserDataSource_RegularUser validates a real user key.
-// Requires PUSHOVER_API_TOKEN and PUSHOVER_USER_KEY to be set.
+// TestValidateUserDataSource_RegularUser validates a user key.
 func TestValidateUserDataSource_RegularUser(t *testing.T) {
-skipIfNoToken(t)
+testAPI(t)
 userKey := os.Getenv("PUSHOVER_USER_KEY")
 if userKey == "" {
 t.Skip("PUSHOVER_USER_KEY not set; skipping acceptance test")
 }
-resource.Test(t, resource.TestCase{
+resource.UnitTest(t, resource.TestCase{
 ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 Steps: []resource.TestStep{
 {
@@ -63,14 +66,13 @@ resource.TestCheckResourceAttrSet("data.pushover_validate_user.check", "devices.
 }
 
 // TestValidateUserDataSource_WithDeviceFilter validates that the device filter is accepted.
-// Requires PUSHOVER_API_TOKEN and PUSHOVER_USER_KEY to be set.
 func TestValidateUserDataSource_WithDeviceFilter(t *testing.T) {
-skipIfNoToken(t)
+testAPI(t)
 userKey := os.Getenv("PUSHOVER_USER_KEY")
 if userKey == "" {
 t.Skip("PUSHOVER_USER_KEY not set; skipping acceptance test")
 }
-resource.Test(t, resource.TestCase{
+resource.UnitTest(t, resource.TestCase{
 ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 Steps: []resource.TestStep{
 {
@@ -88,3 +90,190 @@ resource.TestCheckResourceAttr("data.pushover_validate_user.filtered", "id", use
 },
 })
 }
+
+// ----- pushover_group_health (fake API only) -----
+
+// TestGroupHealthDataSource_ReportsMembersAgainstFakeAPI validates a roster
+// with a healthy, an invalid, a disabled and a device-less member.
+func TestGroupHealthDataSource_ReportsMembersAgainstFakeAPI(t *testing.T) {
+srv := testAPI(t)
+if srv == nil {
+t.Skip("needs a group with known members; only runs against the fake API")
+}
+const unknownKey = "uUnknownUserKey000000000000001"
+for _, m := range []pushover.GroupMember{
+{User: pushovertest.UserKey, Device: "iphone"},
+{User: unknownKey},
+{User: pushovertest.OtherUserKey, Disabled: true},
+{User: pushovertest.OtherUserKey, Device: "watch"},
+} {
+if err := srv.AddGroupMember(pushovertest.GroupKey, m); err != nil {
+t.Fatal(err)
+}
+}
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {}
+
+data "pushover_group_health" "roster" {
+  group_key = "` + pushovertest.GroupKey + `"
+}`,
+Check: resource.ComposeTestCheckFunc(
+resource.TestCheckResourceAttr("data.pushover_group_health.roster", "id", pushovertest.GroupKey),
+resource.TestCheckResourceAttr("data.pushover_group_health.roster", "healthy", "false"),
+resource.TestCheckResourceAttr("data.pushover_group_health.roster", "members.#", "4"),
+resource.TestCheckResourceAttr("data.pushover_group_health.roster", "members.0.valid", "true"),
+resource.TestCheckResourceAttr("data.pushover_group_health.roster", "members.0.device_missing", "false"),
+resource.TestCheckResourceAttr("data.pushover_group_health.roster", "invalid_members.#", "1"),
+resource.TestCheckResourceAttr("data.pushover_group_health.roster", "invalid_members.0", unknownKey),
+resource.TestCheckResourceAttr("data.pushover_group_health.roster", "disabled_members.#", "1"),
+resource.TestCheckResourceAttr("data.pushover_group_health.roster", "disabled_members.0", pushovertest.OtherUserKey),
+resource.TestCheckResourceAttr("data.pushover_group_health.roster", "missing_device_members.#", "1"),
+resource.TestCheckResourceAttr("data.pushover_group_health.roster", "missing_device_members.0", pushovertest.OtherUserKey+"/watch"),
+resource.TestCheckResourceAttr("data.pushover_group_health.roster", "members.3.valid", "true"),
+),
+},
+},
+})
+}
+
+// TestGroupHealthDataSource_APIFailuresAgainstFakeAPI checks that failures
+// other than a rejected member key, such as a throttled API or a rejected
+// application token, are reported as errors rather than as invalid members.
+func TestGroupHealthDataSource_APIFailuresAgainstFakeAPI(t *testing.T) {
+for name, status := range map[string]int{
+"throttled":    http.StatusTooManyRequests,
+"bad token":    http.StatusBadRequest,
+"unauthorized": http.StatusUnauthorized,
+} {
+t.Run(name, func(t *testing.T) {
+srv := testAPI(t)
+if srv == nil {
+t.Skip("only runs against the fake API")
+}
+if err := srv.AddGroupMember(pushovertest.GroupKey, pushover.GroupMember{User: pushovertest.UserKey}); err != nil {
+t.Fatal(err)
+}
+srv.FailNext("users/validate.json", status, "application token is invalid")
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {}
+
+data "pushover_group_health" "roster" {
+  group_key = "` + pushovertest.GroupKey + `"
+}`,
+ExpectError: regexp.MustCompile(`Failed to validate group member`),
+},
+},
+})
+})
+}
+}
+
+// ----- pushover_team -----
+
+// TestTeamDataSource_ReadsTeam reads a team, which has no members in the fake
+// API.
+func TestTeamDataSource_ReadsTeam(t *testing.T) {
+srv := testAPI(t)
+token := testEnv(t, "PUSHOVER_TEAM_TOKEN")
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "` + token + `" }
+data "pushover_team" "ops" {}`,
+Check: resource.ComposeTestCheckFunc(
+resource.TestCheckResourceAttrSet("data.pushover_team.ops", "id"),
+resource.TestCheckResourceAttrSet("data.pushover_team.ops", "members.#"),
+onFake(srv, resource.ComposeTestCheckFunc(
+resource.TestCheckResourceAttr("data.pushover_team.ops", "id", "Fake Team"),
+resource.TestCheckResourceAttr("data.pushover_team.ops", "members.#", "0"),
+)),
+),
+},
+},
+})
+}
+
+// TestTeamDataSource_ClientWithoutTeams expects an error when the configured
+// client cannot manage teams.
+func TestTeamDataSource_ClientWithoutTeams(t *testing.T) {
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: newFakeClient().providerFactories(),
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "fake" }
+data "pushover_team" "ops" {}`,
+ExpectError: regexp.MustCompile(`cannot manage\s+teams`),
+},
+},
+})
+}
+
+// ----- pushover_license_credits -----
+
+// TestLicenseCreditsDataSource_ClientWithoutLicenses expects an error when
+// the configured client cannot read license credits.
+func TestLicenseCreditsDataSource_ClientWithoutLicenses(t *testing.T) {
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: newFakeClient().providerFactories(),
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "fake" }
+data "pushover_license_credits" "left" {}`,
+ExpectError: regexp.MustCompile(`cannot read\s+license\s+credits`),
+},
+},
+})
+}
+
+// TestSoundsDataSource_WithFake reads sounds from an in-memory client.
+func TestSoundsDataSource_WithFake(t *testing.T) {
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: newFakeClient().providerFactories(),
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "fake" }
+data "pushover_sounds" "all" {}`,
+Check: resource.ComposeTestCheckFunc(
+resource.TestCheckResourceAttr("data.pushover_sounds.all", "sounds.%", "2"),
+resource.TestCheckResourceAttr("data.pushover_sounds.all", "sounds.none", "None (silent)"),
+),
+},
+},
+})
+}
+
+// TestValidateUserDataSource_ReplayLicenses reads devices and licenses from
+// a synthetic replay fixture.
+func TestValidateUserDataSource_ReplayLicenses(t *testing.T) {
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: replayProviderFactories(t, "validate_user_data_source"),
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "` + replayKey("PUSHOVER_API_TOKEN", "aREDACTED000000000000000000001") + `" }
+
+data "pushover_validate_user" "check" {
+  user_key = "` + replayKey("PUSHOVER_USER_KEY", "uREDACTED000000000000000000002") + `"
+}`,
+Check: resource.ComposeTestCheckFunc(
+resource.TestCheckResourceAttr("data.pushover_validate_user.check", "is_group", "false"),
+resource.TestCheckResourceAttrSet("data.pushover_validate_user.check", "devices.0"),
+resource.TestCheckResourceAttrSet("data.pushover_validate_user.check", "licenses.0"),
+),
+},
+},
+})
+}
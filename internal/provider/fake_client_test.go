@@ -0,0 +1,154 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/internal/provider"
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+)
+
+// fakeClient is an in-memory pushover.API for unit-testing resource CRUD
+// logic without a Pushover server.
+type fakeClient struct {
+	mu       sync.Mutex
+	messages []pushover.MessageRequest
+	groups   map[string][]pushover.GroupMember
+}
+
+var _ pushover.API = (*fakeClient)(nil)
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{groups: map[string][]pushover.GroupMember{}}
+}
+
+// providerFactories returns provider factories whose clients are all fake.
+func (f *fakeClient) providerFactories() map[string]func() (tfprotov6.ProviderServer, error) {
+	newClient := func(string) pushover.API { return f }
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"pushover": providerserver.NewProtocol6WithError(provider.NewWithClientFactory("test", newClient)()),
+	}
+}
+
+// member returns the group member matching user and device, if any.
+func (f *fakeClient) member(groupKey, user, device string) (pushover.GroupMember, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, m := range f.groups[groupKey] {
+		if m.User == user && m.Device == device {
+			return m, true
+		}
+	}
+	return pushover.GroupMember{}, false
+}
+
+func (f *fakeClient) ok() pushover.APIResponse {
+	return pushover.APIResponse{Status: 1, Request: fmt.Sprintf("req-%d", len(f.messages))}
+}
+
+func (f *fakeClient) SendMessage(_ context.Context, req *pushover.MessageRequest) (*pushover.MessageResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = append(f.messages, *req)
+	resp := &pushover.MessageResponse{APIResponse: f.ok()}
+	if req.Priority == 2 {
+		resp.Receipt = fmt.Sprintf("receipt-%d", len(f.messages))
+	}
+	return resp, nil
+}
+
+func (f *fakeClient) SendMessageToUsers(ctx context.Context, req *pushover.MessageRequest, users []string) (*pushover.MultiMessageResponse, error) {
+	r := *req
+	r.User = fmt.Sprint(users)
+	resp, err := f.SendMessage(ctx, &r)
+	if err != nil {
+		return nil, err
+	}
+	chunk := pushover.MessageChunk{Users: users, Request: resp.Request, Receipt: resp.Receipt}
+	return &pushover.MultiMessageResponse{Chunks: []pushover.MessageChunk{chunk}}, nil
+}
+
+func (f *fakeClient) GetReceipt(_ context.Context, _ string) (*pushover.ReceiptResponse, error) {
+	return &pushover.ReceiptResponse{APIResponse: f.ok()}, nil
+}
+
+func (f *fakeClient) CancelReceipt(_ context.Context, _ string) (*pushover.APIResponse, error) {
+	resp := f.ok()
+	return &resp, nil
+}
+
+func (f *fakeClient) GetSounds(_ context.Context) ([]pushover.Sound, error) {
+	return []pushover.Sound{{Key: "pushover", Name: "Pushover (default)"}, {Key: "none", Name: "None (silent)"}}, nil
+}
+
+func (f *fakeClient) ValidateUser(_ context.Context, _ *pushover.ValidateRequest) (*pushover.ValidateResponse, error) {
+	return &pushover.ValidateResponse{APIResponse: f.ok(), Devices: []string{"phone"}}, nil
+}
+
+func (f *fakeClient) GetGroup(_ context.Context, groupKey string) (*pushover.GroupResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	users := append([]pushover.GroupMember(nil), f.groups[groupKey]...)
+	return &pushover.GroupResponse{APIResponse: f.ok(), Name: "fake", Users: users}, nil
+}
+
+func (f *fakeClient) RenameGroup(_ context.Context, _, _ string) (*pushover.APIResponse, error) {
+	resp := f.ok()
+	return &resp, nil
+}
+
+func (f *fakeClient) AddGroupUser(_ context.Context, groupKey, user, device, memo string) (*pushover.APIResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := f.ok()
+	for i, m := range f.groups[groupKey] {
+		if m.User == user && m.Device == device {
+			f.groups[groupKey][i].Memo = memo
+			return &resp, nil
+		}
+	}
+	f.groups[groupKey] = append(f.groups[groupKey], pushover.GroupMember{User: user, Device: device, Memo: memo})
+	return &resp, nil
+}
+
+func (f *fakeClient) RemoveGroupUser(_ context.Context, groupKey, user, device string) (*pushover.APIResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	members := f.groups[groupKey][:0]
+	for _, m := range f.groups[groupKey] {
+		if m.User != user || m.Device != device {
+			members = append(members, m)
+		}
+	}
+	f.groups[groupKey] = members
+	resp := f.ok()
+	return &resp, nil
+}
+
+func (f *fakeClient) EnableGroupUser(_ context.Context, groupKey, user, device string) (*pushover.APIResponse, error) {
+	return f.setDisabled(groupKey, user, device, false)
+}
+
+func (f *fakeClient) DisableGroupUser(_ context.Context, groupKey, user, device string) (*pushover.APIResponse, error) {
+	return f.setDisabled(groupKey, user, device, true)
+}
+
+func (f *fakeClient) setDisabled(groupKey, user, device string, disabled bool) (*pushover.APIResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, m := range f.groups[groupKey] {
+		if m.User == user && m.Device == device {
+			f.groups[groupKey][i].Disabled = disabled
+			resp := f.ok()
+			return &resp, nil
+		}
+	}
+	return nil, &pushover.APIError{HTTPStatus: 400, Errors: []string{"user is not a member of this group"}}
+}
@@ -0,0 +1,264 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &GlanceResource{}
+var _ resource.ResourceWithConfigValidators = &GlanceResource{}
+
+// NewGlanceResource creates a new glance resource.
+func NewGlanceResource() resource.Resource {
+	return &GlanceResource{}
+}
+
+// GlanceResource pushes glance data to a user's watch complications and
+// widgets.
+type GlanceResource struct {
+	clients *clientSet
+}
+
+// GlanceResourceModel describes the resource data model.
+type GlanceResourceModel struct {
+	UserKey     types.String `tfsdk:"user_key"`
+	Device      types.String `tfsdk:"device"`
+	Application types.String `tfsdk:"application"`
+	Title       types.String `tfsdk:"title"`
+	Text        types.String `tfsdk:"text"`
+	Subtext     types.String `tfsdk:"subtext"`
+	Count       types.Int64  `tfsdk:"count_value"`
+	Percent     types.Int64  `tfsdk:"percent"`
+
+	// Computed
+	ID        types.String `tfsdk:"id"`
+	RequestID types.String `tfsdk:"request_id"`
+}
+
+// glanceAttrs maps client validation errors to the attribute that caused them.
+var glanceAttrs = map[string]path.Path{
+	"user":    path.Root("user_key"),
+	"title":   path.Root("title"),
+	"text":    path.Root("text"),
+	"subtext": path.Root("subtext"),
+	"percent": path.Root("percent"),
+}
+
+func (r *GlanceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_glance"
+}
+
+func (r *GlanceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	glanceText := func(description string) schema.StringAttribute {
+		return schema.StringAttribute{
+			MarkdownDescription: description + fmt.Sprintf(" Up to %d characters.", pushover.MaxGlanceTextLength),
+			Optional:            true,
+			Validators: []validator.String{
+				stringvalidator.LengthAtMost(pushover.MaxGlanceTextLength),
+			},
+		}
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Pushes glance data (a title, text, subtext, count and percentage) to the watch complications and widgets of a Pushover user. " +
+			"The data is pushed when this resource is created and again whenever one of its attributes changes. " +
+			"Glances cannot be read back or deleted, so destroying this resource leaves the last values on the device.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Identifier of the glance target (`user_key[/device]`).",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user_key": schema.StringAttribute{
+				MarkdownDescription: "The Pushover user or group key whose devices show the glance.",
+				Required:            true,
+				Validators: []validator.String{
+					userKeyValidator(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"device": schema.StringAttribute{
+				MarkdownDescription: "Push the glance to only this one of the user's devices.",
+				Optional:            true,
+				Validators: []validator.String{
+					deviceNameValidator(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"application": schema.StringAttribute{
+				MarkdownDescription: "The name of an entry in the provider's `applications` map whose API token pushes the glance. Defaults to the provider-level `api_token`.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"title":   glanceText("A description of the data being shown, such as \"Deploys\"."),
+			"text":    glanceText("The main line of data."),
+			"subtext": glanceText("A second line of data."),
+			"count_value": schema.Int64Attribute{
+				MarkdownDescription: "A number shown on its own, such as a deployment count. May be negative. " +
+					"Sent as the glance's `count` field, a name Terraform reserves.",
+				Optional: true,
+			},
+			"percent": schema.Int64Attribute{
+				MarkdownDescription: "A percentage from 0 to 100, shown as a progress bar or circle, such as a remaining error budget.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 100),
+				},
+			},
+			"request_id": schema.StringAttribute{
+				MarkdownDescription: "The request ID returned by the most recent update.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *GlanceResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.AtLeastOneOf(
+			path.MatchRoot("title"),
+			path.MatchRoot("text"),
+			path.MatchRoot("subtext"),
+			path.MatchRoot("count_value"),
+			path.MatchRoot("percent"),
+		),
+	}
+}
+
+func (r *GlanceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.clients = pd.clients
+}
+
+func (r *GlanceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data GlanceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := data.UserKey.ValueString()
+	if !data.Device.IsNull() {
+		id += "/" + data.Device.ValueString()
+	}
+	data.ID = types.StringValue(id)
+
+	resp.Diagnostics.Append(r.push(ctx, &data, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read does nothing since Pushover glances cannot be retrieved after pushing.
+func (r *GlanceResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {}
+
+func (r *GlanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state GlanceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.push(ctx, &data, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete does nothing since Pushover glances cannot be deleted.
+func (r *GlanceResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+}
+
+// push sends the glance fields in data. Text fields removed since state are
+// sent empty so that they are cleared on the device; a removed count or
+// percent keeps its last value, since the API cannot clear numbers.
+func (r *GlanceResource) push(ctx context.Context, data, state *GlanceResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client, d := r.clients.get(data.Application)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+	glances, ok := client.(pushover.GlanceAPI)
+	if !ok {
+		diags.AddError("Glances Not Supported", fmt.Sprintf("The configured Pushover client (%T) cannot update glances.", client))
+		return diags
+	}
+
+	glanceReq := &pushover.GlanceRequest{
+		User:   data.UserKey.ValueString(),
+		Device: data.Device.ValueString(),
+	}
+	text := func(v types.String, old *types.String) *string {
+		switch {
+		case !v.IsNull():
+			s := v.ValueString()
+			return &s
+		case old != nil && !old.IsNull():
+			s := ""
+			return &s
+		}
+		return nil
+	}
+	var oldTitle, oldText, oldSubtext *types.String
+	if state != nil {
+		oldTitle, oldText, oldSubtext = &state.Title, &state.Text, &state.Subtext
+	}
+	glanceReq.Title = text(data.Title, oldTitle)
+	glanceReq.Text = text(data.Text, oldText)
+	glanceReq.Subtext = text(data.Subtext, oldSubtext)
+	if !data.Count.IsNull() {
+		n := int(data.Count.ValueInt64())
+		glanceReq.Count = &n
+	}
+	if !data.Percent.IsNull() {
+		n := int(data.Percent.ValueInt64())
+		glanceReq.Percent = &n
+	}
+
+	result, err := glances.UpdateGlance(ctx, glanceReq)
+	if err != nil {
+		addClientError(&diags, "Failed to update Pushover glance", err, glanceAttrs)
+		return diags
+	}
+	data.RequestID = types.StringValue(result.Request)
+	return diags
+}
@@ -0,0 +1,184 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider_test
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
+)
+
+// TestGlanceResource_RequiresData checks that a glance without any data is rejected.
+func TestGlanceResource_RequiresData(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_glance" "empty" {
+  user_key = "utest123456789abcdefghijklmnop"
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`At least one of these attributes must be configured`),
+			},
+		},
+	})
+}
+
+// TestGlanceResource_Limits checks Pushover's glance field limits at plan time.
+func TestGlanceResource_Limits(t *testing.T) {
+	t.Parallel()
+	for name, attr := range map[string]string{
+		"percent": `percent = 101`,
+		"title":   fmt.Sprintf("title = %q", strings.Repeat("x", 101)),
+		"device":  `device = "not a device"`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			resource.UnitTest(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_glance" "invalid" {
+  user_key = "utest123456789abcdefghijklmnop"
+  text     = "ok"
+  ` + attr + `
+}`,
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(`Attribute ` + name),
+					},
+				},
+			})
+		})
+	}
+}
+
+// TestGlanceResource_ClientWithoutGlances checks the error reported when the
+// configured client cannot update glances.
+func TestGlanceResource_ClientWithoutGlances(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: newFakeClient().providerFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_glance" "deploys" {
+  user_key = "utest123456789abcdefghijklmnop"
+  count_value = 1
+}`,
+				ExpectError: regexp.MustCompile(`cannot update\s+glances`),
+			},
+		},
+	})
+}
+
+// TestGlanceResource_ChangedApplicationReplaces checks that moving a glance
+// to another application's token replaces it, since Update cannot move it.
+// Both entries hold the same token, which is all the plan looks at.
+func TestGlanceResource_ChangedApplicationReplaces(t *testing.T) {
+	testAPI(t)
+	token, userKey := os.Getenv("PUSHOVER_API_TOKEN"), os.Getenv("PUSHOVER_USER_KEY")
+	config := func(application string) string {
+		return `
+provider "pushover" {
+  applications = {
+    ops  = { api_token = "` + token + `" }
+    team = { api_token = "` + token + `" }
+  }
+}
+
+resource "pushover_glance" "deploys" {
+  user_key    = "` + userKey + `"
+  text        = "ok"
+  application = "` + application + `"
+}`
+	}
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("ops"),
+			},
+			{
+				Config: config("team"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("pushover_glance.deploys", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+// TestGlanceResource_PushAndUpdate pushes a glance and updates it in place.
+// Against the fake API it targets one of the user's devices.
+func TestGlanceResource_PushAndUpdate(t *testing.T) {
+	srv := testAPI(t)
+	userKey := os.Getenv("PUSHOVER_USER_KEY")
+	id, device := userKey, ""
+	if srv != nil {
+		id, device = userKey+"/iphone", `device   = "iphone"`
+	}
+	config := func(body string) string {
+		return `
+provider "pushover" {}
+
+resource "pushover_glance" "deploys" {
+  user_key = "` + userKey + `"
+  ` + device + `
+` + body + `
+}`
+	}
+	checkGlance := func(title string, count, percent int) resource.TestCheckFunc {
+		return onFake(srv, func(*terraform.State) error {
+			g, err := srv.Glance(pushovertest.UserKey, "iphone")
+			if err != nil {
+				return err
+			}
+			if g.Title != title || g.Count == nil || *g.Count != count || g.Percent == nil || *g.Percent != percent {
+				return fmt.Errorf("unexpected glance %+v", g)
+			}
+			return nil
+		})
+	}
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+  title   = "Deploys"
+  count_value = 12
+  percent = 97
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pushover_glance.deploys", "id", id),
+					resource.TestCheckResourceAttrSet("pushover_glance.deploys", "request_id"),
+					checkGlance("Deploys", 12, 97),
+				),
+			},
+			{
+				Config: config(`
+  count_value = 13
+  percent = 95
+`),
+				Check: checkGlance("", 13, 95),
+			},
+		},
+	})
+}
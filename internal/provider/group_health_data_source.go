@@ -0,0 +1,311 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &GroupHealthDataSource{}
+
+// NewGroupHealthDataSource creates a new group health data source.
+func NewGroupHealthDataSource() datasource.DataSource {
+	return &GroupHealthDataSource{}
+}
+
+// GroupHealthDataSource reports on the state of every member of a Pushover delivery group.
+type GroupHealthDataSource struct {
+	clients *clientSet
+}
+
+// GroupHealthDataSourceModel describes the data source data model.
+type GroupHealthDataSourceModel struct {
+	GroupKey    types.String `tfsdk:"group_key"`
+	Application types.String `tfsdk:"application"`
+	// Computed
+	ID                   types.String `tfsdk:"id"`
+	Name                 types.String `tfsdk:"name"`
+	Healthy              types.Bool   `tfsdk:"healthy"`
+	Members              types.List   `tfsdk:"members"`
+	InvalidMembers       types.List   `tfsdk:"invalid_members"`
+	DisabledMembers      types.List   `tfsdk:"disabled_members"`
+	MissingDeviceMembers types.List   `tfsdk:"missing_device_members"`
+}
+
+// groupHealthMemberModel describes a single entry of the members attribute.
+type groupHealthMemberModel struct {
+	ID            types.String `tfsdk:"id"`
+	UserKey       types.String `tfsdk:"user_key"`
+	Device        types.String `tfsdk:"device"`
+	Memo          types.String `tfsdk:"memo"`
+	Disabled      types.Bool   `tfsdk:"disabled"`
+	Valid         types.Bool   `tfsdk:"valid"`
+	DeviceMissing types.Bool   `tfsdk:"device_missing"`
+	Licenses      types.List   `tfsdk:"licenses"`
+}
+
+var groupHealthMemberAttrTypes = map[string]attr.Type{
+	"id":             types.StringType,
+	"user_key":       types.StringType,
+	"device":         types.StringType,
+	"memo":           types.StringType,
+	"disabled":       types.BoolType,
+	"valid":          types.BoolType,
+	"device_missing": types.BoolType,
+	"licenses":       types.ListType{ElemType: types.StringType},
+}
+
+func (d *GroupHealthDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_health"
+}
+
+func (d *GroupHealthDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Loads a Pushover delivery group and validates every member. " +
+			"Reports members whose keys are invalid, who are disabled, or who are bound to a device that no longer exists. " +
+			"Intended for use in `check` blocks to alert on roster rot.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The group key (used as resource identifier).",
+				Computed:            true,
+			},
+			"group_key": schema.StringAttribute{
+				MarkdownDescription: "The Pushover delivery group key to inspect.",
+				Required:            true,
+				Validators: []validator.String{
+					groupKeyValidator(),
+				},
+			},
+			"application": schema.StringAttribute{
+				MarkdownDescription: "The name of an entry in the provider's `applications` map whose API token is used to read and validate the group. Defaults to the provider-level `api_token`.",
+				Optional:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the delivery group.",
+				Computed:            true,
+			},
+			"healthy": schema.BoolAttribute{
+				MarkdownDescription: "`true` when no member is invalid, disabled, or bound to a missing device.",
+				Computed:            true,
+			},
+			"members": schema.ListNestedAttribute{
+				MarkdownDescription: "Every member of the group along with its validation result.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Member identifier (`user_key[/device]`).",
+							Computed:            true,
+						},
+						"user_key": schema.StringAttribute{
+							MarkdownDescription: "The member's Pushover user key.",
+							Computed:            true,
+						},
+						"device": schema.StringAttribute{
+							MarkdownDescription: "The device the membership is bound to, if any.",
+							Computed:            true,
+						},
+						"memo": schema.StringAttribute{
+							MarkdownDescription: "The memo stored with the membership.",
+							Computed:            true,
+						},
+						"disabled": schema.BoolAttribute{
+							MarkdownDescription: "`true` if the member is disabled in the group.",
+							Computed:            true,
+						},
+						"valid": schema.BoolAttribute{
+							MarkdownDescription: "`true` if the user key passed validation.",
+							Computed:            true,
+						},
+						"device_missing": schema.BoolAttribute{
+							MarkdownDescription: "`true` if the membership is bound to a device the user no longer has.",
+							Computed:            true,
+						},
+						"licenses": schema.ListAttribute{
+							MarkdownDescription: "The license types active for this member.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+			"invalid_members": schema.ListAttribute{
+				MarkdownDescription: "Identifiers of members whose user key failed validation.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"disabled_members": schema.ListAttribute{
+				MarkdownDescription: "Identifiers of members that are disabled in the group.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"missing_device_members": schema.ListAttribute{
+				MarkdownDescription: "Identifiers of members bound to a device that is no longer registered.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (d *GroupHealthDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.clients = pd.clients
+}
+
+func (d *GroupHealthDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data GroupHealthDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := d.clients.get(data.Application)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	groupKey := data.GroupKey.ValueString()
+	groupResp, err := client.GetGroup(ctx, groupKey)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Failed to read group", err, map[string]path.Path{"group": path.Root("group_key")})
+		return
+	}
+
+	// A user may appear several times (once per device), so validate each
+	// key and device pair only once.
+	validated := make(map[string]*pushover.ValidateResponse)
+	validate := func(user, device string) (*pushover.ValidateResponse, error) {
+		key := user + "/" + device
+		if result, seen := validated[key]; seen {
+			return result, nil
+		}
+		result, err := client.ValidateUser(ctx, &pushover.ValidateRequest{User: user, Device: device})
+		if err != nil {
+			if !keyRejected(err) {
+				return nil, err
+			}
+			// The API rejected the key or device; record it as invalid.
+			result = nil
+		}
+		validated[key] = result
+		return result, nil
+	}
+
+	members := make([]groupHealthMemberModel, 0, len(groupResp.Users))
+	invalid := make([]string, 0)
+	disabled := make([]string, 0)
+	missingDevice := make([]string, 0)
+
+	for _, member := range groupResp.Users {
+		result, err := validate(member.User, member.Device)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to validate group member", err.Error())
+			return
+		}
+		deviceMissing := false
+		if result == nil && member.Device != "" {
+			// Tell a device the user no longer has apart from an invalid key.
+			result, err = validate(member.User, "")
+			if err != nil {
+				resp.Diagnostics.AddError("Failed to validate group member", err.Error())
+				return
+			}
+			deviceMissing = result != nil
+		}
+
+		id := member.User
+		if member.Device != "" {
+			id += "/" + member.Device
+		}
+		valid := result != nil
+
+		var licenses []string
+		if valid {
+			licenses = result.Licenses
+		}
+		licensesTF, diags := types.ListValueFrom(ctx, types.StringType, append([]string{}, licenses...))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		members = append(members, groupHealthMemberModel{
+			ID:            types.StringValue(id),
+			UserKey:       types.StringValue(member.User),
+			Device:        types.StringValue(member.Device),
+			Memo:          types.StringValue(member.Memo),
+			Disabled:      types.BoolValue(member.Disabled),
+			Valid:         types.BoolValue(valid),
+			DeviceMissing: types.BoolValue(deviceMissing),
+			Licenses:      licensesTF,
+		})
+
+		if !valid {
+			invalid = append(invalid, id)
+		}
+		if member.Disabled {
+			disabled = append(disabled, id)
+		}
+		if deviceMissing {
+			missingDevice = append(missingDevice, id)
+		}
+	}
+
+	membersTF, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: groupHealthMemberAttrTypes}, members)
+	resp.Diagnostics.Append(diags...)
+	invalidTF, diags := types.ListValueFrom(ctx, types.StringType, invalid)
+	resp.Diagnostics.Append(diags...)
+	disabledTF, diags := types.ListValueFrom(ctx, types.StringType, disabled)
+	resp.Diagnostics.Append(diags...)
+	missingDeviceTF, diags := types.ListValueFrom(ctx, types.StringType, missingDevice)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = data.GroupKey
+	data.Name = types.StringValue(groupResp.Name)
+	data.Healthy = types.BoolValue(len(invalid) == 0 && len(disabled) == 0 && len(missingDevice) == 0)
+	data.Members = membersTF
+	data.InvalidMembers = invalidTF
+	data.DisabledMembers = disabledTF
+	data.MissingDeviceMembers = missingDeviceTF
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// keyRejected reports whether err is the API refusing the member's key or
+// device: a 400 response flagging the "user" or "device" field. Any other
+// failure, such as a rejected token or a throttled, failing or unreachable
+// API, says nothing about the member.
+func keyRejected(err error) bool {
+	var apiErr *pushover.APIError
+	return errors.As(err, &apiErr) &&
+		apiErr.HTTPStatus == http.StatusBadRequest &&
+		(apiErr.FieldInvalid("user") || apiErr.FieldInvalid("device"))
+}
@@ -7,12 +7,13 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/Josh-Archer/terraform-provider-pushover/internal/pushover"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -26,7 +27,7 @@ func NewGroupUserResource() resource.Resource {
 
 // GroupUserResource manages a user's membership in a Pushover delivery group.
 type GroupUserResource struct {
-	client *pushover.Client
+	clients *clientSet
 }
 
 // GroupUserResourceModel describes the resource data model.
@@ -36,10 +37,18 @@ type GroupUserResourceModel struct {
 	Device   types.String `tfsdk:"device"`
 	Memo     types.String `tfsdk:"memo"`
 	Disabled types.Bool   `tfsdk:"disabled"`
+	// Application selects a client from the provider's applications map.
+	Application types.String `tfsdk:"application"`
 	// Computed ID to ensure uniqueness in state
 	ID types.String `tfsdk:"id"`
 }
 
+// groupUserKeyAttrs maps client validation errors to the attribute that supplied the key.
+var groupUserKeyAttrs = map[string]path.Path{
+	"group": path.Root("group_key"),
+	"user":  path.Root("user_key"),
+}
+
 func (r *GroupUserResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_group_user"
 }
@@ -59,6 +68,9 @@ func (r *GroupUserResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"group_key": schema.StringAttribute{
 				MarkdownDescription: "The Pushover delivery group key.",
 				Required:            true,
+				Validators: []validator.String{
+					groupKeyValidator(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -66,6 +78,9 @@ func (r *GroupUserResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"user_key": schema.StringAttribute{
 				MarkdownDescription: "The Pushover user key to add to the group.",
 				Required:            true,
+				Validators: []validator.String{
+					userKeyValidator(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -73,6 +88,9 @@ func (r *GroupUserResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"device": schema.StringAttribute{
 				MarkdownDescription: "Optionally restrict notifications to a specific device for this user.",
 				Optional:            true,
+				Validators: []validator.String{
+					deviceNameValidator(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -80,6 +98,16 @@ func (r *GroupUserResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"memo": schema.StringAttribute{
 				MarkdownDescription: "An optional note about this group member (up to 200 characters).",
 				Optional:            true,
+				Validators: []validator.String{
+					memoValidator(),
+				},
+			},
+			"application": schema.StringAttribute{
+				MarkdownDescription: "The name of an entry in the provider's `applications` map whose API token is used to manage this membership. Defaults to the provider-level `api_token`.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"disabled": schema.BoolAttribute{
 				MarkdownDescription: "Set to `true` to disable notifications to this user without removing them from the group.",
@@ -95,15 +123,15 @@ func (r *GroupUserResource) Configure(_ context.Context, req resource.ConfigureR
 	if req.ProviderData == nil {
 		return
 	}
-	client, ok := req.ProviderData.(*pushover.Client)
+	pd, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *pushover.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
-	r.client = client
+	r.clients = pd.clients
 }
 
 func (r *GroupUserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -113,14 +141,20 @@ func (r *GroupUserResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
+	client, diags := r.clients.get(data.Application)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	groupKey := data.GroupKey.ValueString()
 	userKey := data.UserKey.ValueString()
 	device := data.Device.ValueString()
 	memo := data.Memo.ValueString()
 
-	_, err := r.client.AddGroupUser(ctx, groupKey, userKey, device, memo)
+	_, err := client.AddGroupUser(ctx, groupKey, userKey, device, memo)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to add user to group", err.Error())
+		addClientError(&resp.Diagnostics, "Failed to add user to group", err, groupUserKeyAttrs)
 		return
 	}
 
@@ -133,8 +167,8 @@ func (r *GroupUserResource) Create(ctx context.Context, req resource.CreateReque
 
 	// Apply disabled state if requested
 	if !data.Disabled.IsNull() && data.Disabled.ValueBool() {
-		if _, err := r.client.DisableGroupUser(ctx, groupKey, userKey, device); err != nil {
-			resp.Diagnostics.AddError("Failed to disable group user", err.Error())
+		if _, err := client.DisableGroupUser(ctx, groupKey, userKey, device); err != nil {
+			addClientError(&resp.Diagnostics, "Failed to disable group user", err, groupUserKeyAttrs)
 			return
 		}
 	}
@@ -149,13 +183,19 @@ func (r *GroupUserResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	client, diags := r.clients.get(data.Application)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	groupKey := data.GroupKey.ValueString()
 	userKey := data.UserKey.ValueString()
 	device := data.Device.ValueString()
 
-	groupResp, err := r.client.GetGroup(ctx, groupKey)
+	groupResp, err := client.GetGroup(ctx, groupKey)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to read group", err.Error())
+		addClientError(&resp.Diagnostics, "Failed to read group", err, groupUserKeyAttrs)
 		return
 	}
 
@@ -189,14 +229,20 @@ func (r *GroupUserResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
+	client, diags := r.clients.get(data.Application)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	groupKey := data.GroupKey.ValueString()
 	userKey := data.UserKey.ValueString()
 	device := data.Device.ValueString()
 
 	// Handle memo update by re-adding
 	if data.Memo != state.Memo {
-		if _, err := r.client.AddGroupUser(ctx, groupKey, userKey, device, data.Memo.ValueString()); err != nil {
-			resp.Diagnostics.AddError("Failed to update group user memo", err.Error())
+		if _, err := client.AddGroupUser(ctx, groupKey, userKey, device, data.Memo.ValueString()); err != nil {
+			addClientError(&resp.Diagnostics, "Failed to update group user memo", err, groupUserKeyAttrs)
 			return
 		}
 	}
@@ -204,13 +250,13 @@ func (r *GroupUserResource) Update(ctx context.Context, req resource.UpdateReque
 	// Handle enable/disable toggle
 	if data.Disabled != state.Disabled {
 		if data.Disabled.ValueBool() {
-			if _, err := r.client.DisableGroupUser(ctx, groupKey, userKey, device); err != nil {
-				resp.Diagnostics.AddError("Failed to disable group user", err.Error())
+			if _, err := client.DisableGroupUser(ctx, groupKey, userKey, device); err != nil {
+				addClientError(&resp.Diagnostics, "Failed to disable group user", err, groupUserKeyAttrs)
 				return
 			}
 		} else {
-			if _, err := r.client.EnableGroupUser(ctx, groupKey, userKey, device); err != nil {
-				resp.Diagnostics.AddError("Failed to enable group user", err.Error())
+			if _, err := client.EnableGroupUser(ctx, groupKey, userKey, device); err != nil {
+				addClientError(&resp.Diagnostics, "Failed to enable group user", err, groupUserKeyAttrs)
 				return
 			}
 		}
@@ -226,12 +272,18 @@ func (r *GroupUserResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
+	client, diags := r.clients.get(data.Application)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	groupKey := data.GroupKey.ValueString()
 	userKey := data.UserKey.ValueString()
 	device := data.Device.ValueString()
 
-	if _, err := r.client.RemoveGroupUser(ctx, groupKey, userKey, device); err != nil {
-		resp.Diagnostics.AddError("Failed to remove user from group", err.Error())
+	if _, err := client.RemoveGroupUser(ctx, groupKey, userKey, device); err != nil {
+		addClientError(&resp.Diagnostics, "Failed to remove user from group", err, groupUserKeyAttrs)
 		return
 	}
 }
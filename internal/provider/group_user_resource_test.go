@@ -4,9 +4,17 @@
 package provider_test
 
 import (
+"fmt"
+"net/http"
+"net/http/httptest"
+"regexp"
 "testing"
 
 "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+"github.com/hashicorp/terraform-plugin-testing/plancheck"
+"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
 )
 
 // TestGroupUserResource_BasicSchema validates the minimal required fields are accepted.
@@ -20,8 +28,8 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_group_user" "basic" {
-  group_key = "gtest123456789abcdefghijklmnopqrs"
-  user_key  = "utest123456789abcdefghijklmnopqrs"
+  group_key = "gtest123456789abcdefghijklmnop"
+  user_key  = "utest123456789abcdefghijklmnop"
 }`,
 PlanOnly:           true,
 ExpectNonEmptyPlan: true,
@@ -41,8 +49,8 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_group_user" "with_device" {
-  group_key = "gtest123456789abcdefghijklmnopqrs"
-  user_key  = "utest123456789abcdefghijklmnopqrs"
+  group_key = "gtest123456789abcdefghijklmnop"
+  user_key  = "utest123456789abcdefghijklmnop"
   device    = "iphone"
   memo      = "Primary device"
 }`,
@@ -64,8 +72,8 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_group_user" "disabled" {
-  group_key = "gtest123456789abcdefghijklmnopqrs"
-  user_key  = "utest123456789abcdefghijklmnopqrs"
+  group_key = "gtest123456789abcdefghijklmnop"
+  user_key  = "utest123456789abcdefghijklmnop"
   disabled  = true
 }`,
 PlanOnly:           true,
@@ -86,8 +94,8 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_group_user" "default_enabled" {
-  group_key = "gtest123456789abcdefghijklmnopqrs"
-  user_key  = "utest123456789abcdefghijklmnopqrs"
+  group_key = "gtest123456789abcdefghijklmnop"
+  user_key  = "utest123456789abcdefghijklmnop"
 }`,
 PlanOnly:           true,
 ExpectNonEmptyPlan: true,
@@ -113,8 +121,8 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_group_user" "plan_id" {
-  group_key = "gABC"
-  user_key  = "uXYZ"
+  group_key = "gABCdefghijklmnopqrstuvwxyz123"
+  user_key  = "uXYZdefghijklmnopqrstuvwxyz123"
 }`,
 PlanOnly:           true,
 ExpectNonEmptyPlan: true,
@@ -134,8 +142,8 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_group_user" "no_memo" {
-  group_key = "gABC"
-  user_key  = "uXYZ"
+  group_key = "gABCdefghijklmnopqrstuvwxyz123"
+  user_key  = "uXYZdefghijklmnopqrstuvwxyz123"
   memo      = ""
 }`,
 PlanOnly:           true,
@@ -144,3 +152,216 @@ ExpectNonEmptyPlan: true,
 },
 })
 }
+
+// TestGroupUserResource_MalformedGroupKey expects a malformed group key to be
+// rejected on the group_key attribute without calling the API.
+func TestGroupUserResource_MalformedGroupKey(t *testing.T) {
+t.Parallel()
+srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+t.Error("no request should be sent for a malformed group key")
+w.WriteHeader(http.StatusInternalServerError)
+}))
+defer srv.Close()
+
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  api_token = "fake"
+  base_url  = "` + srv.URL + `"
+}
+
+resource "pushover_group_user" "bad" {
+  group_key = "gABC/../users"
+  user_key  = "uQiRzpo4DXghDmr9QzzfQu27cmVRsG"
+}`,
+ExpectError: regexp.MustCompile(`(?i)invalid group key`),
+},
+},
+})
+}
+
+// TestGroupUserResource_InvalidDeviceAndMemo expects validation errors for a bad device name and an over-long memo.
+func TestGroupUserResource_InvalidDeviceAndMemo(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_group_user" "bad" {
+  group_key = "gtest123456789abcdefghijklmnop"
+  user_key  = "utest123456789abcdefghijklmnop"
+  device    = "my phone"
+  memo      = "${join("", [for i in range(201) : "x"])}"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?s)(Invalid Device Name.*Memo Too Long|Memo Too Long.*Invalid Device Name)`),
+},
+},
+})
+}
+
+// TestGroupUserResource_LifecycleWithFake exercises create, update and
+// delete against an in-memory client.
+func TestGroupUserResource_LifecycleWithFake(t *testing.T) {
+fake := newFakeClient()
+const groupKey, userKey = "gABCdefghijklmnopqrstuvwxyz123", "uABCdefghijklmnopqrstuvwxyz123"
+config := func(memo string, disabled bool) string {
+return fmt.Sprintf(`
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_group_user" "m" {
+  group_key = %q
+  user_key  = %q
+  device    = "phone"
+  memo      = %q
+  disabled  = %t
+}`, groupKey, userKey, memo, disabled)
+}
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: fake.providerFactories(),
+Steps: []resource.TestStep{
+{
+Config: config("on call", false),
+Check: resource.ComposeTestCheckFunc(
+resource.TestCheckResourceAttr("pushover_group_user.m", "id", groupKey+"/"+userKey+"/phone"),
+func(*terraform.State) error {
+if m, ok := fake.member(groupKey, userKey, "phone"); !ok || m.Memo != "on call" || m.Disabled {
+return fmt.Errorf("unexpected member: %+v (found=%t)", m, ok)
+}
+return nil
+},
+),
+},
+{
+Config: config("backup", true),
+Check: func(*terraform.State) error {
+if m, ok := fake.member(groupKey, userKey, "phone"); !ok || m.Memo != "backup" || !m.Disabled {
+return fmt.Errorf("unexpected member: %+v (found=%t)", m, ok)
+}
+return nil
+},
+},
+},
+CheckDestroy: func(*terraform.State) error {
+if _, ok := fake.member(groupKey, userKey, "phone"); ok {
+return fmt.Errorf("member still in group after destroy")
+}
+return nil
+},
+})
+}
+
+// TestGroupUserResource_ChangedApplicationReplaces checks that moving a
+// membership to another application's token replaces it, since Update cannot
+// move it.
+func TestGroupUserResource_ChangedApplicationReplaces(t *testing.T) {
+fake := newFakeClient()
+config := func(application string) string {
+return `
+provider "pushover" {
+  applications = {
+    ops  = { api_token = "fake-ops" }
+    team = { api_token = "fake-team" }
+  }
+}
+
+resource "pushover_group_user" "m" {
+  group_key   = "gABCdefghijklmnopqrstuvwxyz123"
+  user_key    = "uABCdefghijklmnopqrstuvwxyz123"
+  application = "` + application + `"
+}`
+}
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: fake.providerFactories(),
+Steps: []resource.TestStep{
+{
+Config: config("ops"),
+},
+{
+Config: config("team"),
+ConfigPlanChecks: resource.ConfigPlanChecks{
+PreApply: []plancheck.PlanCheck{
+plancheck.ExpectResourceAction("pushover_group_user.m", plancheck.ResourceActionDestroyBeforeCreate),
+},
+},
+},
+},
+})
+}
+
+// TestGroupUserResource_AgainstFakeAPI manages a member of a pushovertest
+// group and recreates it after it is removed outside Terraform.
+func TestGroupUserResource_AgainstFakeAPI(t *testing.T) {
+srv := testAPI(t)
+if srv == nil {
+t.Skip("modifies a group; only runs against the fake API")
+}
+config := `
+provider "pushover" {}
+
+resource "pushover_group_user" "oncall" {
+  group_key = "` + pushovertest.GroupKey + `"
+  user_key  = "` + pushovertest.UserKey + `"
+  device    = "iphone"
+  memo      = "primary"
+}`
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: config,
+Check: resource.TestCheckResourceAttr("pushover_group_user.oncall", "disabled", "false"),
+},
+{
+PreConfig: func() {
+if err := srv.RemoveGroupMember(pushovertest.GroupKey, pushovertest.UserKey, "iphone"); err != nil {
+t.Fatal(err)
+}
+},
+Config: config,
+Check: func(*terraform.State) error {
+_, members, _ := srv.Group(pushovertest.GroupKey)
+if len(members) != 1 || members[0].Memo != "primary" {
+return fmt.Errorf("expected the member to be re-added, got %+v", members)
+}
+return nil
+},
+},
+},
+CheckDestroy: func(*terraform.State) error {
+if _, members, _ := srv.Group(pushovertest.GroupKey); len(members) != 0 {
+return fmt.Errorf("expected an empty group after destroy, got %+v", members)
+}
+return nil
+},
+})
+}
+
+// TestGroupUserResource_UnknownUserAgainstFakeAPI checks that the API's
+// rejection of an unknown user is reported.
+func TestGroupUserResource_UnknownUserAgainstFakeAPI(t *testing.T) {
+if testAPI(t) == nil {
+t.Skip("only runs against the fake API")
+}
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {}
+
+resource "pushover_group_user" "ghost" {
+  group_key = "` + pushovertest.GroupKey + `"
+  user_key  = "uUnknownUserKey000000000000000"
+}`,
+ExpectError: regexp.MustCompile(`user key is invalid`),
+},
+},
+})
+}
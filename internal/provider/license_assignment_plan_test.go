@@ -0,0 +1,96 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func licenseModel(userKey, os, application string) *LicenseAssignmentResourceModel {
+	m := &LicenseAssignmentResourceModel{
+		UserKey:     types.StringValue(userKey),
+		Email:       types.StringNull(),
+		OS:          types.StringNull(),
+		Application: types.StringNull(),
+		ID:          types.StringValue(userKey),
+		RequestID:   types.StringValue("req-1"),
+	}
+	if os != "" {
+		m.OS = types.StringValue(os)
+		m.ID = types.StringValue(userKey + "/" + os)
+	}
+	if application != "" {
+		m.Application = types.StringValue(application)
+	}
+	return m
+}
+
+// planLicense runs ModifyPlan for a change from state to plan, either of
+// which may be nil, and returns the warnings it reports.
+func planLicense(t *testing.T, state, plan *LicenseAssignmentResourceModel) []string {
+	t.Helper()
+	ctx := context.Background()
+	r := &LicenseAssignmentResource{}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	req := resource.ModifyPlanRequest{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+		Plan:  tfsdk.Plan{Schema: schemaResp.Schema},
+	}
+	if state != nil {
+		if diags := req.State.Set(ctx, state); diags.HasError() {
+			t.Fatal(diags)
+		}
+	}
+	if plan != nil {
+		if diags := req.Plan.Set(ctx, plan); diags.HasError() {
+			t.Fatal(diags)
+		}
+	}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	var warnings []string
+	for _, d := range resp.Diagnostics.Warnings() {
+		warnings = append(warnings, d.Summary())
+	}
+	return warnings
+}
+
+func TestLicenseAssignment_ModifyPlanWarnsOnReplacement(t *testing.T) {
+	const user = "uQiRzpo4DXghDmr9QzzfQu27cmVRsG"
+	cases := []struct {
+		name         string
+		state, plan  *LicenseAssignmentResourceModel
+		wantWarnings int
+	}{
+		{"create", nil, licenseModel(user, "Desktop", ""), 0},
+		{"destroy", licenseModel(user, "Desktop", ""), nil, 0},
+		{"unchanged", licenseModel(user, "Desktop", ""), licenseModel(user, "Desktop", ""), 0},
+		{"application", licenseModel(user, "Desktop", ""), licenseModel(user, "Desktop", "owner"), 0},
+		{"os", licenseModel(user, "Desktop", ""), licenseModel(user, "iOS", ""), 1},
+		{"user_key", licenseModel(user, "", ""), licenseModel("gznej3rKEVAvPUxu9vvNnqpmZpokzF", "", ""), 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings := planLicense(t, tc.state, tc.plan)
+			if len(warnings) != tc.wantWarnings {
+				t.Fatalf("warnings = %q, want %d", warnings, tc.wantWarnings)
+			}
+			for _, w := range warnings {
+				if w != "License Assignment Will Spend Another Credit" {
+					t.Errorf("unexpected warning %q", w)
+				}
+			}
+		})
+	}
+}
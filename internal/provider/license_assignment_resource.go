@@ -0,0 +1,231 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &LicenseAssignmentResource{}
+var _ resource.ResourceWithConfigValidators = &LicenseAssignmentResource{}
+var _ resource.ResourceWithModifyPlan = &LicenseAssignmentResource{}
+
+// NewLicenseAssignmentResource creates a new license assignment resource.
+func NewLicenseAssignmentResource() resource.Resource {
+	return &LicenseAssignmentResource{}
+}
+
+// LicenseAssignmentResource assigns a prepaid Pushover license to a user.
+type LicenseAssignmentResource struct {
+	clients *clientSet
+}
+
+// LicenseAssignmentResourceModel describes the resource data model.
+type LicenseAssignmentResourceModel struct {
+	UserKey     types.String `tfsdk:"user_key"`
+	Email       types.String `tfsdk:"email"`
+	OS          types.String `tfsdk:"os"`
+	Application types.String `tfsdk:"application"`
+
+	// Computed
+	ID        types.String `tfsdk:"id"`
+	RequestID types.String `tfsdk:"request_id"`
+}
+
+// licenseAssignmentAttrs maps client validation errors to the attribute that supplied the value.
+var licenseAssignmentAttrs = map[string]path.Path{
+	"user":  path.Root("user_key"),
+	"email": path.Root("email"),
+	"os":    path.Root("os"),
+}
+
+func (r *LicenseAssignmentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_license_assignment"
+}
+
+func (r *LicenseAssignmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	requiresReplace := []planmodifier.String{
+		stringplanmodifier.RequiresReplace(),
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Assigns one of the application owner's prepaid Pushover license credits to a user, identified by user key or email address. " +
+			"Each new assignment spends a credit; check the balance with the `pushover_license_credits` data source. " +
+			"Licenses cannot be read back or revoked through the API, so destroying this resource only removes it from state, " +
+			"and changing `user_key`, `email` or `os` assigns a second license, spending another credit.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Identifier of the assignment (`user_key` or `email`, followed by `/os` when set).",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user_key": schema.StringAttribute{
+				MarkdownDescription: "The user key to license. Exactly one of `user_key` and `email` must be set.",
+				Optional:            true,
+				Validators: []validator.String{
+					userKeyValidator(),
+				},
+				PlanModifiers: requiresReplace,
+			},
+			"email": schema.StringAttribute{
+				MarkdownDescription: "The email address to license. Pushover creates an account for it if needed.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(emailPattern, "must be an email address"),
+				},
+				PlanModifiers: requiresReplace,
+			},
+			"os": schema.StringAttribute{
+				MarkdownDescription: "The platform to license: `Android`, `iOS` or `Desktop`. When unset, the license applies to the first platform the user activates.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pushover.LicensePlatforms...),
+				},
+				PlanModifiers: requiresReplace,
+			},
+			"application": schema.StringAttribute{
+				MarkdownDescription: "The name of an entry in the provider's `applications` map whose owner's credits are spent. Defaults to the provider-level `api_token`. " +
+					"Only used when the license is assigned; changing it later does not assign a new license.",
+				Optional: true,
+			},
+			"request_id": schema.StringAttribute{
+				MarkdownDescription: "The request ID returned by the assignment.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *LicenseAssignmentResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("user_key"),
+			path.MatchRoot("email"),
+		),
+	}
+}
+
+// ModifyPlan warns when the plan replaces an assignment, since the new
+// license spends another credit while the old one cannot be revoked.
+func (r *LicenseAssignmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Creating and destroying assignments need no warning.
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state LicenseAssignmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for name, changed := range map[string]bool{
+		"user_key": !plan.UserKey.Equal(state.UserKey),
+		"email":    !plan.Email.Equal(state.Email),
+		"os":       !plan.OS.Equal(state.OS),
+	} {
+		if changed {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root(name),
+				"License Assignment Will Spend Another Credit",
+				fmt.Sprintf("Changing %s replaces this assignment with a new one, which spends another of the application owner's prepaid license credits. "+
+					"The license already assigned to %s cannot be revoked through the API and is kept by its user.", name, state.ID.ValueString()),
+			)
+		}
+	}
+}
+
+func (r *LicenseAssignmentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.clients = pd.clients
+}
+
+func (r *LicenseAssignmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data LicenseAssignmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.clients.get(data.Application)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	licenses, ok := client.(pushover.LicenseAPI)
+	if !ok {
+		resp.Diagnostics.AddError("Licenses Not Supported", fmt.Sprintf("The configured Pushover client (%T) cannot assign licenses.", client))
+		return
+	}
+
+	result, err := licenses.AssignLicense(ctx, &pushover.LicenseAssignRequest{
+		User:  data.UserKey.ValueString(),
+		Email: data.Email.ValueString(),
+		OS:    data.OS.ValueString(),
+	})
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Failed to assign Pushover license", err, licenseAssignmentAttrs)
+		return
+	}
+
+	id := data.UserKey.ValueString() + data.Email.ValueString()
+	if !data.OS.IsNull() {
+		id += "/" + data.OS.ValueString()
+	}
+	data.ID = types.StringValue(id)
+	data.RequestID = types.StringValue(result.Request)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read does nothing since Pushover licenses cannot be retrieved.
+func (r *LicenseAssignmentResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+}
+
+// Update only records a new application, which has no effect once the
+// license is assigned; every other change requires replacement.
+func (r *LicenseAssignmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state LicenseAssignmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.ID = state.ID
+	data.RequestID = state.RequestID
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete does nothing since Pushover licenses cannot be revoked through the API.
+func (r *LicenseAssignmentResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+}
@@ -0,0 +1,179 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider_test
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
+)
+
+// TestLicenseAssignmentResource_BasicSchema validates the required and optional fields are accepted.
+func TestLicenseAssignmentResource_BasicSchema(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_license_assignment" "ada" {
+  email = "ada@example.com"
+  os    = "Desktop"
+}`,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// TestLicenseAssignmentResource_RecipientRequired expects an error unless exactly one of user_key and email is set.
+func TestLicenseAssignmentResource_RecipientRequired(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_license_assignment" "both" {
+  user_key = "utest123456789abcdefghijklmnop"
+  email    = "ada@example.com"
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Exactly one of these attributes must be configured`),
+			},
+		},
+	})
+}
+
+// TestLicenseAssignmentResource_InvalidOS expects a validation error for an unknown platform.
+func TestLicenseAssignmentResource_InvalidOS(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_license_assignment" "bad" {
+  user_key = "utest123456789abcdefghijklmnop"
+  os       = "BeOS"
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+		},
+	})
+}
+
+// TestLicenseAssignmentResource_AssignAndCount licenses a user key and an
+// email address, reads the remaining credits, and moves an assignment to
+// another application without spending a credit. Against the real API it
+// spends two credits, so it only runs when PUSHOVER_LICENSE_EMAIL is set.
+func TestLicenseAssignmentResource_AssignAndCount(t *testing.T) {
+	srv := testAPI(t)
+	email := testEnv(t, "PUSHOVER_LICENSE_EMAIL")
+	userKey := os.Getenv("PUSHOVER_USER_KEY")
+	licenseCount := func(want int) resource.TestCheckFunc {
+		return onFake(srv, func(*terraform.State) error {
+			if got := srv.Licenses(); len(got) != want {
+				return fmt.Errorf("expected %d licenses, got %+v", want, got)
+			}
+			return nil
+		})
+	}
+	config := func(application string) string {
+		return `
+provider "pushover" {
+  applications = {
+    owner = { api_token = "` + os.Getenv("PUSHOVER_API_TOKEN") + `" }
+  }
+}
+
+resource "pushover_license_assignment" "desktop" {
+  user_key    = "` + userKey + `"
+  os          = "Desktop"
+  ` + application + `
+}
+
+resource "pushover_license_assignment" "new_hire" {
+  email = "` + email + `"
+}
+
+data "pushover_license_credits" "left" {
+  depends_on = [pushover_license_assignment.desktop, pushover_license_assignment.new_hire]
+}
+
+data "pushover_validate_user" "licensed" {
+  user_key   = pushover_license_assignment.desktop.user_key
+  depends_on = [pushover_license_assignment.desktop]
+}`
+	}
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pushover_license_assignment.desktop", "id", userKey+"/Desktop"),
+					resource.TestCheckResourceAttr("pushover_license_assignment.new_hire", "id", email),
+					resource.TestCheckResourceAttrSet("pushover_license_assignment.new_hire", "request_id"),
+					resource.TestCheckResourceAttrSet("data.pushover_license_credits.left", "credits"),
+					resource.TestCheckTypeSetElemAttr("data.pushover_validate_user.licensed", "licenses.*", "Desktop"),
+					onFake(srv, resource.TestCheckResourceAttr("data.pushover_license_credits.left", "credits", fmt.Sprint(pushovertest.DefaultCredits-2))),
+					licenseCount(2),
+				),
+			},
+			{
+				Config: config(`application = "owner"`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("pushover_license_assignment.desktop", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pushover_license_assignment.desktop", "application", "owner"),
+					resource.TestCheckResourceAttrSet("pushover_license_assignment.desktop", "request_id"),
+					licenseCount(2),
+				),
+			},
+		},
+	})
+}
+
+// TestLicenseAssignmentResource_NoCreditsAgainstFakeAPI checks that the API's
+// rejection of an assignment without credits is reported.
+func TestLicenseAssignmentResource_NoCreditsAgainstFakeAPI(t *testing.T) {
+	srv := testAPI(t)
+	if srv == nil {
+		t.Skip("needs an account without credits; only runs against the fake API")
+	}
+	srv.SetCredits(0)
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pushover" {}
+
+resource "pushover_license_assignment" "ada" {
+  email = "ada@example.com"
+}`,
+				ExpectError: regexp.MustCompile(`no license credits remain`),
+			},
+		},
+	})
+}
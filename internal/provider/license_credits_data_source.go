@@ -0,0 +1,105 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &LicenseCreditsDataSource{}
+
+// NewLicenseCreditsDataSource creates a new license credits data source.
+func NewLicenseCreditsDataSource() datasource.DataSource {
+	return &LicenseCreditsDataSource{}
+}
+
+// LicenseCreditsDataSource reports the license credits left to assign.
+type LicenseCreditsDataSource struct {
+	clients *clientSet
+}
+
+// LicenseCreditsDataSourceModel describes the data source data model.
+type LicenseCreditsDataSourceModel struct {
+	Application types.String `tfsdk:"application"`
+	// Computed
+	ID      types.String `tfsdk:"id"`
+	Credits types.Int64  `tfsdk:"credits"`
+}
+
+func (d *LicenseCreditsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_license_credits"
+}
+
+func (d *LicenseCreditsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Returns the number of prepaid Pushover license credits the application's owner has left to assign with `pushover_license_assignment`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Placeholder identifier (always `license_credits`).",
+				Computed:            true,
+			},
+			"application": schema.StringAttribute{
+				MarkdownDescription: "The name of an entry in the provider's `applications` map whose owner's credits are reported. Defaults to the provider-level `api_token`.",
+				Optional:            true,
+			},
+			"credits": schema.Int64Attribute{
+				MarkdownDescription: "The number of license credits left to assign.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *LicenseCreditsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.clients = pd.clients
+}
+
+func (d *LicenseCreditsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data LicenseCreditsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := d.clients.get(data.Application)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	licenses, ok := client.(pushover.LicenseAPI)
+	if !ok {
+		resp.Diagnostics.AddError("Licenses Not Supported", fmt.Sprintf("The configured Pushover client (%T) cannot read license credits.", client))
+		return
+	}
+
+	credits, err := licenses.GetLicenseCredits(ctx)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Failed to read license credits", err, nil)
+		return
+	}
+
+	data.ID = types.StringValue("license_credits")
+	data.Credits = types.Int64Value(int64(credits.Credits))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,28 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// sensitiveLogFields are masked in every log entry, in addition to the
+// redaction the client already applies.
+var sensitiveLogFields = []string{"token", "user"}
+
+// tflogLogger forwards client logs to tflog so they appear with TF_LOG.
+type tflogLogger struct{}
+
+var _ pushover.Logger = tflogLogger{}
+
+func (tflogLogger) Debug(ctx context.Context, msg string, fields map[string]interface{}) {
+	tflog.Debug(tflog.MaskFieldValuesWithFieldKeys(ctx, sensitiveLogFields...), msg, fields)
+}
+
+func (tflogLogger) Trace(ctx context.Context, msg string, fields map[string]interface{}) {
+	tflog.Trace(tflog.MaskFieldValuesWithFieldKeys(ctx, sensitiveLogFields...), msg, fields)
+}
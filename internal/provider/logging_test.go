@@ -0,0 +1,43 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestTflogLogger_MasksSensitiveFields(t *testing.T) {
+	var out bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &out)
+
+	tflogLogger{}.Trace(ctx, "Sending Pushover API request", map[string]interface{}{
+		"path":  "/messages.json",
+		"token": "raw_token_value",
+		"user":  "raw_user_value",
+	})
+	tflogLogger{}.Debug(ctx, "Pushover API call", map[string]interface{}{"status": 200})
+
+	logged := out.String()
+	entries, err := tflogtest.MultilineJSONDecode(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 log entries, got %d", len(entries))
+	}
+	if entries[0]["@level"] != "trace" || entries[1]["@level"] != "debug" {
+		t.Errorf("unexpected levels: %v, %v", entries[0]["@level"], entries[1]["@level"])
+	}
+	if entries[0]["path"] != "/messages.json" {
+		t.Errorf("expected path to be logged, got %v", entries[0]["path"])
+	}
+	if strings.Contains(logged, "raw_token_value") || strings.Contains(logged, "raw_user_value") {
+		t.Errorf("log output leaks a secret: %s", logged)
+	}
+}
@@ -5,15 +5,24 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
-	"github.com/Josh-Archer/terraform-provider-pushover/internal/pushover"
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -21,6 +30,8 @@ import (
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &MessageResource{}
+var _ resource.ResourceWithConfigValidators = &MessageResource{}
+var _ resource.ResourceWithModifyPlan = &MessageResource{}
 
 // NewMessageResource creates a new message resource.
 func NewMessageResource() resource.Resource {
@@ -29,27 +40,34 @@ func NewMessageResource() resource.Resource {
 
 // MessageResource defines the resource implementation.
 type MessageResource struct {
-	client *pushover.Client
+	clients  *clientSet
+	defaults *messageDefaults
+	profiles map[string]messageProfile
 }
 
 // MessageResourceModel describes the resource data model.
 type MessageResourceModel struct {
 	// Required
-	UserKey types.String `tfsdk:"user_key"`
 	Message types.String `tfsdk:"message"`
 
+	// Recipients (exactly one of)
+	UserKey  types.String `tfsdk:"user_key"`
+	UserKeys types.Set    `tfsdk:"user_keys"`
+
 	// Optional sending fields
-	APIToken  types.String `tfsdk:"api_token"`
-	Title     types.String `tfsdk:"title"`
-	URL       types.String `tfsdk:"url"`
-	URLTitle  types.String `tfsdk:"url_title"`
-	Priority  types.Int64  `tfsdk:"priority"`
-	Sound     types.String `tfsdk:"sound"`
-	Device    types.String `tfsdk:"device"`
-	Timestamp types.Int64  `tfsdk:"timestamp"`
-	HTML      types.Bool   `tfsdk:"html"`
-	Monospace types.Bool   `tfsdk:"monospace"`
-	TTL       types.Int64  `tfsdk:"ttl"`
+	Profile     types.String `tfsdk:"profile"`
+	Application types.String `tfsdk:"application"`
+	APIToken    types.String `tfsdk:"api_token"`
+	Title       types.String `tfsdk:"title"`
+	URL         types.String `tfsdk:"url"`
+	URLTitle    types.String `tfsdk:"url_title"`
+	Priority    types.Int64  `tfsdk:"priority"`
+	Sound       types.String `tfsdk:"sound"`
+	Device      types.String `tfsdk:"device"`
+	Timestamp   types.Int64  `tfsdk:"timestamp"`
+	HTML        types.Bool   `tfsdk:"html"`
+	Monospace   types.Bool   `tfsdk:"monospace"`
+	TTL         types.Int64  `tfsdk:"ttl"`
 
 	// Emergency priority (priority=2) fields
 	Retry    types.Int64  `tfsdk:"retry"`
@@ -57,8 +75,11 @@ type MessageResourceModel struct {
 	Callback types.String `tfsdk:"callback"`
 
 	// Computed
-	Receipt   types.String `tfsdk:"receipt"`
-	RequestID types.String `tfsdk:"request_id"`
+	EffectiveTitle types.String `tfsdk:"effective_title"`
+	Receipt        types.String `tfsdk:"receipt"`
+	RequestID      types.String `tfsdk:"request_id"`
+	Receipts       types.List   `tfsdk:"receipts"`
+	RequestIDs     types.List   `tfsdk:"request_ids"`
 }
 
 func (r *MessageResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -71,11 +92,29 @@ func (r *MessageResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 			"To resend the message (e.g., when content changes), use `terraform taint` or update a trigger via `replace_triggered_by`.",
 		Attributes: map[string]schema.Attribute{
 			"user_key": schema.StringAttribute{
-				MarkdownDescription: "The Pushover user or group key to deliver the message to.",
-				Required:            true,
+				MarkdownDescription: "The Pushover user or group key to deliver the message to. Conflicts with `user_keys`. " +
+					"Defaults to the provider's `defaults.user_key`; one of the two must be set when `user_keys` is not.",
+				Optional: true,
+				Computed: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					userKeyValidator(),
+				},
+			},
+			"user_keys": schema.SetAttribute{
+				MarkdownDescription: "A set of Pushover user or group keys to deliver the message to. " +
+					"Keys are sent in batches of 50 per API call. Conflicts with `user_key`.",
+				Optional:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(userKeyValidator()),
+				},
 			},
 			"message": schema.StringAttribute{
 				MarkdownDescription: "The message body (up to 1024 characters). Supports HTML if `html` is enabled.",
@@ -87,6 +126,21 @@ func (r *MessageResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 					stringvalidator.LengthBetween(1, 1024),
 				},
 			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "The name of a `profile` block in the provider configuration. " +
+					"The profile supplies `priority`, `sound`, `device`, `retry` and `expire` unless they are set on this resource.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"application": schema.StringAttribute{
+				MarkdownDescription: "The name of an entry in the provider's `applications` map whose API token is used to send this message. Defaults to the provider-level `api_token`.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"api_token": schema.StringAttribute{
 				MarkdownDescription: "Override the provider-level Pushover application API token for this message.",
 				Optional:            true,
@@ -126,28 +180,38 @@ func (r *MessageResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				},
 			},
 			"priority": schema.Int64Attribute{
-				MarkdownDescription: "Message priority: `-2` (lowest), `-1` (low), `0` (normal, default), `1` (high), `2` (emergency).",
-				Optional:            true,
-				Computed:            true,
-				Default:             int64default.StaticInt64(0),
+				MarkdownDescription: "Message priority: `-2` (lowest), `-1` (low), `0` (normal, default), `1` (high), `2` (emergency). " +
+					"Defaults to the priority of the selected `profile`, or `0`.",
+				Optional: true,
+				Computed: true,
 				Validators: []validator.Int64{
 					int64validator.Between(-2, 2),
 				},
 				PlanModifiers: []planmodifier.Int64{},
 			},
 			"sound": schema.StringAttribute{
-				MarkdownDescription: "The name of a Pushover sound to override the user's default. Use the `pushover_sounds` data source to list available sounds.",
-				Optional:            true,
+				MarkdownDescription: "The name of a Pushover sound to override the user's default. Use the `pushover_sounds` data source to list available sounds. " +
+					"Defaults to the selected `profile`, then the provider's `defaults.sound`.",
+				Optional: true,
+				Computed: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					soundValidator(),
+				},
 			},
 			"device": schema.StringAttribute{
-				MarkdownDescription: "The name of a specific device to deliver the message to, rather than all of the user's devices.",
-				Optional:            true,
+				MarkdownDescription: "The name of a specific device, or a comma-separated list of devices, to deliver the message to, rather than all of the user's devices. " +
+					"Defaults to the selected `profile`, then the provider's `defaults.device`.",
+				Optional: true,
+				Computed: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					deviceListValidator(),
+				},
 			},
 			"timestamp": schema.Int64Attribute{
 				MarkdownDescription: "A Unix timestamp to display instead of the time the message was received.",
@@ -173,16 +237,20 @@ func (r *MessageResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				PlanModifiers: []planmodifier.Int64{},
 			},
 			"retry": schema.Int64Attribute{
-				MarkdownDescription: "How often (in seconds) to re-send an emergency message until acknowledged. Required when `priority` is `2`. Minimum: 30.",
-				Optional:            true,
+				MarkdownDescription: "How often (in seconds) to re-send an emergency message until acknowledged. Required when `priority` is `2` " +
+					"unless the selected `profile` or the provider's `defaults.retry` supplies it. Minimum: 30.",
+				Optional: true,
+				Computed: true,
 				Validators: []validator.Int64{
 					int64validator.AtLeast(30),
 				},
 				PlanModifiers: []planmodifier.Int64{},
 			},
 			"expire": schema.Int64Attribute{
-				MarkdownDescription: "How long (in seconds) to continue re-sending an emergency message. Required when `priority` is `2`. Maximum: 10800.",
-				Optional:            true,
+				MarkdownDescription: "How long (in seconds) to continue re-sending an emergency message. Required when `priority` is `2` " +
+					"unless the selected `profile` or the provider's `defaults.expire` supplies it. Maximum: 10800.",
+				Optional: true,
+				Computed: true,
 				Validators: []validator.Int64{
 					int64validator.Between(1, 10800),
 				},
@@ -195,6 +263,10 @@ func (r *MessageResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"effective_title": schema.StringAttribute{
+				MarkdownDescription: "The title that is sent, including the provider's `defaults.title_prefix`.",
+				Computed:            true,
+			},
 			"receipt": schema.StringAttribute{
 				MarkdownDescription: "Receipt token returned for emergency (`priority = 2`) messages. Use `pushover_receipt` data source to poll delivery status.",
 				Computed:            true,
@@ -203,47 +275,241 @@ func (r *MessageResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				},
 			},
 			"request_id": schema.StringAttribute{
-				MarkdownDescription: "The unique request ID returned by the Pushover API.",
+				MarkdownDescription: "The unique request ID returned by the Pushover API. When `user_keys` is used, this is the request ID of the first successful batch.",
 				Computed:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"receipts": schema.ListAttribute{
+				MarkdownDescription: "Receipt tokens for every batch sent, in the same order as `request_ids`. Empty strings for batches without a receipt or that failed.",
+				Computed:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"request_ids": schema.ListAttribute{
+				MarkdownDescription: "Request IDs for every batch sent, one per 50 keys of `user_keys` in order. Empty strings for batches that failed.",
+				Computed:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
 
+func (r *MessageResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("user_key"),
+			path.MatchRoot("user_keys"),
+		),
+	}
+}
+
 func (r *MessageResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
-	client, ok := req.ProviderData.(*pushover.Client)
+	pd, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *pushover.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
-	r.client = client
+	r.clients = pd.clients
+	r.defaults = &pd.defaults
+	r.profiles = pd.profiles
+}
+
+// ModifyPlan merges the provider-level message defaults into the plan so the
+// effective values are visible before apply.
+func (r *MessageResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy, or before the provider has been configured.
+	if req.Plan.Raw.IsNull() || r.defaults == nil {
+		return
+	}
+
+	var plan, config MessageResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.applyDefaults(&plan, &config)...)
+	if !config.Application.IsUnknown() {
+		_, diags := r.clients.getWithOverride(config.Application, config.APIToken)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state MessageResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// Attribute plan modifiers run before ModifyPlan, so a changed default
+		// has to request replacement itself.
+		for name, changed := range map[string]bool{
+			"user_key":        !plan.UserKey.Equal(state.UserKey),
+			"sound":           !plan.Sound.Equal(state.Sound),
+			"device":          !plan.Device.Equal(state.Device),
+			"effective_title": !plan.EffectiveTitle.Equal(state.EffectiveTitle),
+			"priority":        !plan.Priority.Equal(state.Priority),
+			"retry":           !plan.Retry.Equal(state.Retry),
+			"expire":          !plan.Expire.Equal(state.Expire),
+		} {
+			if changed {
+				resp.RequiresReplace = append(resp.RequiresReplace, path.Root(name))
+			}
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// applyDefaults resolves every attribute that falls back to a message profile
+// or a provider default. Attributes set in config always win, then the
+// selected profile, then the provider defaults. Unknown config values stay unknown.
+func (r *MessageResource) applyDefaults(plan, config *MessageResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	d := r.defaults
+
+	var p messageProfile
+	pending := config.Profile.IsUnknown()
+	if !config.Profile.IsNull() && !pending {
+		name := config.Profile.ValueString()
+		var ok bool
+		if p, ok = r.profiles[name]; !ok {
+			names := make([]string, 0, len(r.profiles))
+			for n := range r.profiles {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			diags.AddAttributeError(
+				path.Root("profile"),
+				"Unknown Message Profile",
+				fmt.Sprintf("No profile named %q is defined in the provider configuration. Defined profiles: [%s].", name, strings.Join(names, ", ")),
+			)
+			return diags
+		}
+	}
+
+	if config.UserKeys.IsNull() {
+		plan.UserKey = resolveString(config.UserKey, false, d.UserKey)
+		if plan.UserKey.IsNull() {
+			diags.AddAttributeError(
+				path.Root("user_key"),
+				"Missing Recipient",
+				"Set user_key or user_keys on the resource, or configure defaults.user_key (PUSHOVER_USER_KEY) on the provider.",
+			)
+		}
+	} else {
+		plan.UserKey = types.StringNull()
+	}
+	plan.Sound = resolveString(config.Sound, pending, p.Sound, d.Sound)
+	plan.Device = resolveString(config.Device, pending, p.Device, d.Device)
+	plan.Priority = resolveInt64(config.Priority, pending, p.Priority)
+	if plan.Priority.IsNull() {
+		plan.Priority = types.Int64Value(0)
+	}
+
+	switch {
+	case config.Title.IsUnknown():
+		plan.EffectiveTitle = types.StringUnknown()
+	case config.Title.IsNull():
+		plan.EffectiveTitle = types.StringNull()
+	default:
+		plan.EffectiveTitle = types.StringValue(d.TitlePrefix + config.Title.ValueString())
+	}
+
+	// Emergency settings only fall back for priority 2 messages.
+	switch {
+	case plan.Priority.IsUnknown():
+		plan.Retry = resolveInt64(config.Retry, pending || p.Retry != 0 || d.Retry != 0)
+		plan.Expire = resolveInt64(config.Expire, pending || p.Expire != 0 || d.Expire != 0)
+	case plan.Priority.ValueInt64() == 2:
+		plan.Retry = resolveInt64(config.Retry, pending, p.Retry, d.Retry)
+		plan.Expire = resolveInt64(config.Expire, pending, p.Expire, d.Expire)
+	default:
+		plan.Retry, plan.Expire = config.Retry, config.Expire
+	}
+
+	return diags
+}
+
+// resolveString returns v when it is set. Otherwise it returns unknown when
+// pending, or the first non-empty fallback, or null.
+func resolveString(v types.String, pending bool, fallbacks ...string) types.String {
+	if !v.IsNull() {
+		return v
+	}
+	if pending {
+		return types.StringUnknown()
+	}
+	for _, f := range fallbacks {
+		if f != "" {
+			return types.StringValue(f)
+		}
+	}
+	return types.StringNull()
+}
+
+// resolveInt64 returns v when it is set. Otherwise it returns unknown when
+// pending, or the first non-zero fallback, or null.
+func resolveInt64(v types.Int64, pending bool, fallbacks ...int64) types.Int64 {
+	if !v.IsNull() {
+		return v
+	}
+	if pending {
+		return types.Int64Unknown()
+	}
+	for _, f := range fallbacks {
+		if f != 0 {
+			return types.Int64Value(f)
+		}
+	}
+	return types.Int64Null()
 }
 
 func (r *MessageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var data MessageResourceModel
+	var data, config MessageResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Values that were unknown at plan time are resolved again now that the
+	// whole configuration is known.
+	resp.Diagnostics.Append(r.applyDefaults(&data, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.clients.getWithOverride(data.Application, data.APIToken)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	msgReq := &pushover.MessageRequest{
-		User:    data.UserKey.ValueString(),
 		Message: data.Message.ValueString(),
 	}
 	if !data.APIToken.IsNull() && !data.APIToken.IsUnknown() {
 		msgReq.Token = data.APIToken.ValueString()
 	}
-	if !data.Title.IsNull() {
-		msgReq.Title = data.Title.ValueString()
+	if !data.EffectiveTitle.IsNull() {
+		msgReq.Title = data.EffectiveTitle.ValueString()
 	}
 	if !data.URL.IsNull() {
 		msgReq.URL = data.URL.ValueString()
@@ -287,7 +553,18 @@ func (r *MessageResource) Create(ctx context.Context, req resource.CreateRequest
 		}
 	}
 
-	result, err := r.client.SendMessage(ctx, msgReq)
+	if !data.UserKeys.IsNull() {
+		var userKeys []string
+		resp.Diagnostics.Append(data.UserKeys.ElementsAs(ctx, &userKeys, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		r.sendToUsers(ctx, client, msgReq, userKeys, &data, resp)
+		return
+	}
+
+	msgReq.User = data.UserKey.ValueString()
+	result, err := client.SendMessage(ctx, msgReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to send Pushover message", err.Error())
 		return
@@ -295,10 +572,60 @@ func (r *MessageResource) Create(ctx context.Context, req resource.CreateRequest
 
 	data.Receipt = types.StringValue(result.Receipt)
 	data.RequestID = types.StringValue(result.Request)
+	data.Receipts = types.ListValueMust(types.StringType, []attr.Value{types.StringValue(result.Receipt)})
+	data.RequestIDs = types.ListValueMust(types.StringType, []attr.Value{types.StringValue(result.Request)})
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// sendToUsers delivers the message to every key in userKeys, recording the
+// request ID and receipt of each batch. When only some batches fail, the
+// successful batches are still saved to state so their receipts are not lost;
+// failed batches keep their position with empty values.
+func (r *MessageResource) sendToUsers(ctx context.Context, client pushover.API, msgReq *pushover.MessageRequest, userKeys []string, data *MessageResourceModel, resp *resource.CreateResponse) {
+	result, err := client.SendMessageToUsers(ctx, msgReq, userKeys)
+	var partial *pushover.PartialSendError
+	if err != nil && !errors.As(err, &partial) {
+		resp.Diagnostics.AddError("Failed to send Pushover message", err.Error())
+		return
+	}
+	if partial != nil && len(partial.FailedUsers) == partial.Total {
+		resp.Diagnostics.AddError("Failed to send Pushover message", err.Error())
+		return
+	}
+
+	// Keep one entry per batch, empty for failed batches, so that positions
+	// line up with the batches of 50 keys in user_keys.
+	receipts := make([]attr.Value, 0, len(result.Chunks))
+	requestIDs := make([]attr.Value, 0, len(result.Chunks))
+	data.Receipt, data.RequestID = types.StringNull(), types.StringNull()
+	for _, chunk := range result.Chunks {
+		if chunk.Err != nil {
+			receipts = append(receipts, types.StringValue(""))
+			requestIDs = append(requestIDs, types.StringValue(""))
+			continue
+		}
+		receipts = append(receipts, types.StringValue(chunk.Receipt))
+		requestIDs = append(requestIDs, types.StringValue(chunk.Request))
+		if data.RequestID.IsNull() {
+			data.Receipt = types.StringValue(chunk.Receipt)
+			data.RequestID = types.StringValue(chunk.Request)
+		}
+	}
+	data.Receipts = types.ListValueMust(types.StringType, receipts)
+	data.RequestIDs = types.ListValueMust(types.StringType, requestIDs)
+
+	if partial != nil {
+		resp.Diagnostics.AddError(
+			"Message Partially Delivered",
+			fmt.Sprintf("The message was sent to %d of %d recipients. Batches that failed have an empty entry in request_ids.\n\n%s",
+				partial.Total-len(partial.FailedUsers), partial.Total, err.Error()),
+		)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+}
+
 // Read does nothing since Pushover messages cannot be retrieved after sending.
 func (r *MessageResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {}
 
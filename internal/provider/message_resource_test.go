@@ -4,10 +4,18 @@
 package provider_test
 
 import (
+"fmt"
+"net/http"
+"os"
 "regexp"
+"strings"
 "testing"
 
 "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+"github.com/hashicorp/terraform-plugin-testing/plancheck"
+"github.com/hashicorp/terraform-plugin-testing/terraform"
+"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 )
 
 // TestMessageResource_Schema validates the minimal required fields are accepted.
@@ -21,7 +29,7 @@ Config: `
 provider "pushover" { api_token = "fake_token_for_schema_test" }
 
 resource "pushover_message" "test" {
-  user_key = "utest1234567890abcdefghijklmnopqr"
+  user_key = "utest1234567890abcdefghijklmno"
   message  = "Hello from Terraform!"
   title    = "Test"
   priority = 0
@@ -46,7 +54,7 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_message" "full" {
-  user_key   = "utest1234567890abcdefghijklmnopqr"
+  user_key   = "utest1234567890abcdefghijklmno"
   message    = "Detailed message"
   title      = "Detailed Title"
   url        = "https://example.com"
@@ -76,7 +84,7 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_message" "emergency" {
-  user_key = "utest1234567890abcdefghijklmnopqr"
+  user_key = "utest1234567890abcdefghijklmno"
   message  = "Emergency!"
   priority = 2
   retry    = 60
@@ -101,7 +109,7 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_message" "low" {
-  user_key = "utest1234567890abcdefghijklmnopqr"
+  user_key = "utest1234567890abcdefghijklmno"
   message  = "quiet notification"
   priority = -2
 }`,
@@ -125,7 +133,7 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_message" "bad" {
-  user_key = "utest1234567890abcdefghijklmnopqr"
+  user_key = "utest1234567890abcdefghijklmno"
   message  = "Bad priority"
   priority = 5
 }`,
@@ -153,7 +161,7 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_message" "long" {
-  user_key = "utest1234567890abcdefghijklmnopqr"
+  user_key = "utest1234567890abcdefghijklmno"
   message  = "` + string(longMsg) + `"
 }`,
 PlanOnly:    true,
@@ -174,7 +182,7 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_message" "neg_ttl" {
-  user_key = "utest1234567890abcdefghijklmnopqr"
+  user_key = "utest1234567890abcdefghijklmno"
   message  = "test"
   ttl      = -1
 }`,
@@ -196,7 +204,7 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_message" "low_retry" {
-  user_key = "utest1234567890abcdefghijklmnopqr"
+  user_key = "utest1234567890abcdefghijklmno"
   message  = "test"
   priority = 2
   retry    = 10
@@ -220,7 +228,7 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_message" "big_expire" {
-  user_key = "utest1234567890abcdefghijklmnopqr"
+  user_key = "utest1234567890abcdefghijklmno"
   message  = "test"
   priority = 2
   retry    = 30
@@ -250,7 +258,7 @@ Config: `
 provider "pushover" { api_token = "fake" }
 
 resource "pushover_message" "long_title" {
-  user_key = "utest1234567890abcdefghijklmnopqr"
+  user_key = "utest1234567890abcdefghijklmno"
   message  = "test"
   title    = "` + string(longTitle) + `"
 }`,
@@ -260,3 +268,450 @@ ExpectError: regexp.MustCompile(`(?i)(length|characters)`),
 },
 })
 }
+
+// ----- Multiple recipients -----
+
+// TestMessageResource_UserKeys validates that a set of recipients is accepted.
+func TestMessageResource_UserKeys(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_message" "fan_out" {
+  user_keys = ["utest1234567890abcdefghijklmno", "utest0987654321abcdefghijklmno"]
+  message   = "Hello, everyone"
+}`,
+PlanOnly:           true,
+ExpectNonEmptyPlan: true,
+},
+},
+})
+}
+
+// TestMessageResource_UserKeyAndUserKeysConflict expects an error when both recipient forms are set.
+func TestMessageResource_UserKeyAndUserKeysConflict(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_message" "both" {
+  user_key  = "utest1234567890abcdefghijklmno"
+  user_keys = ["utest0987654321abcdefghijklmno"]
+  message   = "test"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)invalid attribute combination`),
+},
+},
+})
+}
+
+// TestMessageResource_MissingRecipient expects an error when neither recipient form is set
+// and the provider has no default user key.
+func TestMessageResource_MissingRecipient(t *testing.T) {
+t.Setenv("PUSHOVER_USER_KEY", "")
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_message" "nobody" {
+  message = "test"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)missing recipient`),
+},
+},
+})
+}
+
+// TestMessageResource_EmptyUserKeys expects an error for an empty set of recipients.
+func TestMessageResource_EmptyUserKeys(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_message" "empty" {
+  user_keys = []
+  message   = "test"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)(at least 1|invalid)`),
+},
+},
+})
+}
+
+// ----- Message profiles -----
+
+const testProfilesProviderConfig = `
+provider "pushover" {
+  api_token = "fake"
+
+  profile {
+    name     = "critical"
+    priority = 2
+    sound    = "siren"
+    retry    = 60
+    expire   = 3600
+  }
+
+  profile {
+    name     = "warning"
+    priority = 1
+    sound    = "magic"
+  }
+
+  profile {
+    name     = "info"
+    priority = -1
+  }
+}
+`
+
+// TestMessageResource_ProfileResolved validates that profile settings appear in the plan.
+func TestMessageResource_ProfileResolved(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: testProfilesProviderConfig + `
+resource "pushover_message" "critical" {
+  user_key = "utest1234567890abcdefghijklmno"
+  message  = "Database down"
+  profile  = "critical"
+}`,
+PlanOnly:           true,
+ExpectNonEmptyPlan: true,
+ConfigPlanChecks: resource.ConfigPlanChecks{
+PostApplyPreRefresh: []plancheck.PlanCheck{
+plancheck.ExpectKnownValue("pushover_message.critical", tfjsonpath.New("priority"), knownvalue.Int64Exact(2)),
+plancheck.ExpectKnownValue("pushover_message.critical", tfjsonpath.New("sound"), knownvalue.StringExact("siren")),
+plancheck.ExpectKnownValue("pushover_message.critical", tfjsonpath.New("retry"), knownvalue.Int64Exact(60)),
+plancheck.ExpectKnownValue("pushover_message.critical", tfjsonpath.New("expire"), knownvalue.Int64Exact(3600)),
+},
+},
+},
+},
+})
+}
+
+// TestMessageResource_ProfileOverriddenByResource validates that explicit attributes win over the profile.
+func TestMessageResource_ProfileOverriddenByResource(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: testProfilesProviderConfig + `
+resource "pushover_message" "warning" {
+  user_key = "utest1234567890abcdefghijklmno"
+  message  = "Disk at 80%"
+  profile  = "warning"
+  sound    = "bike"
+}`,
+PlanOnly:           true,
+ExpectNonEmptyPlan: true,
+ConfigPlanChecks: resource.ConfigPlanChecks{
+PostApplyPreRefresh: []plancheck.PlanCheck{
+plancheck.ExpectKnownValue("pushover_message.warning", tfjsonpath.New("priority"), knownvalue.Int64Exact(1)),
+plancheck.ExpectKnownValue("pushover_message.warning", tfjsonpath.New("sound"), knownvalue.StringExact("bike")),
+},
+},
+},
+},
+})
+}
+
+// TestMessageResource_ChangedProfileResendsMessage validates that changing
+// a profile's priority replaces messages that use it.
+func TestMessageResource_ChangedProfileResendsMessage(t *testing.T) {
+testAPI(t)
+config := func(priority int) string {
+return fmt.Sprintf(`
+provider "pushover" {
+  profile {
+    name     = "status"
+    priority = %d
+  }
+}
+
+resource "pushover_message" "status" {
+  message = "Deploy finished"
+  profile = "status"
+}`, priority)
+}
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: config(-1),
+},
+{
+Config: config(0),
+ConfigPlanChecks: resource.ConfigPlanChecks{
+PreApply: []plancheck.PlanCheck{
+plancheck.ExpectResourceAction("pushover_message.status", plancheck.ResourceActionReplace),
+},
+},
+Check: resource.TestCheckResourceAttr("pushover_message.status", "priority", "0"),
+},
+},
+})
+}
+
+// TestMessageResource_UnknownProfile expects a plan-time error for an undefined profile.
+func TestMessageResource_UnknownProfile(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: testProfilesProviderConfig + `
+resource "pushover_message" "typo" {
+  user_key = "utest1234567890abcdefghijklmno"
+  message  = "test"
+  profile  = "critcal"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)unknown message profile`),
+},
+},
+})
+}
+
+// TestMessageResource_DuplicateProfile expects duplicate profile names to be
+// rejected when the configuration is validated. No token is configured, so
+// Configure would fail with a different error if validation let it run.
+func TestMessageResource_DuplicateProfile(t *testing.T) {
+t.Setenv("PUSHOVER_API_TOKEN", "")
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  profile {
+    name     = "critical"
+    priority = 2
+  }
+
+  profile {
+    name     = "critical"
+    priority = 1
+  }
+}
+
+resource "pushover_message" "probe" {
+  user_key = "utest1234567890abcdefghijklmno"
+  message  = "probe"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`Duplicate Message Profile`),
+},
+},
+})
+}
+
+// TestMessageResource_PriorityDefaultsToNormal validates priority is 0 without a profile.
+func TestMessageResource_PriorityDefaultsToNormal(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_message" "normal" {
+  user_key = "utest1234567890abcdefghijklmno"
+  message  = "test"
+}`,
+PlanOnly:           true,
+ExpectNonEmptyPlan: true,
+ConfigPlanChecks: resource.ConfigPlanChecks{
+PostApplyPreRefresh: []plancheck.PlanCheck{
+plancheck.ExpectKnownValue("pushover_message.normal", tfjsonpath.New("priority"), knownvalue.Int64Exact(0)),
+},
+},
+},
+},
+})
+}
+
+// TestMessageResource_InvalidUserKeysElement expects every element of user_keys to be validated.
+func TestMessageResource_InvalidUserKeysElement(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "test_token" }
+
+resource "pushover_message" "bad" {
+  user_keys = ["utest1234567890abcdefghijklmno", "not-a-key"]
+  message   = "test"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)invalid user key`),
+},
+},
+})
+}
+
+// TestMessageResource_SendWithFake checks the request built from the
+// resource and the receipt saved to state, using an in-memory client.
+func TestMessageResource_SendWithFake(t *testing.T) {
+fake := newFakeClient()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: fake.providerFactories(),
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  api_token = "fake"
+  defaults { title_prefix = "[ci] " }
+}
+
+resource "pushover_message" "alert" {
+  user_key = "uABCdefghijklmnopqrstuvwxyz123"
+  title    = "Deploy"
+  message  = "Deploy failed"
+  priority = 2
+  retry    = 60
+  expire   = 3600
+}`,
+Check: resource.ComposeTestCheckFunc(
+resource.TestCheckResourceAttr("pushover_message.alert", "receipt", "receipt-1"),
+resource.TestCheckResourceAttr("pushover_message.alert", "request_id", "req-1"),
+func(*terraform.State) error {
+if len(fake.messages) != 1 {
+return fmt.Errorf("expected 1 message, got %d", len(fake.messages))
+}
+if got := fake.messages[0]; got.Title != "[ci] Deploy" || got.Retry != 60 || got.Expire != 3600 {
+return fmt.Errorf("unexpected request: %s", got.String())
+}
+return nil
+},
+),
+},
+},
+})
+}
+
+// TestMessageResource_EmergencyAgainstFakeAPI sends an emergency message
+// through the HTTP client to the pushovertest server.
+func TestMessageResource_EmergencyAgainstFakeAPI(t *testing.T) {
+srv := testAPI(t)
+if srv == nil {
+t.Skip("sends an emergency notification; only runs against the fake API")
+}
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {}
+
+resource "pushover_message" "page" {
+  user_key = "` + os.Getenv("PUSHOVER_USER_KEY") + `"
+  message  = "Database unreachable"
+  sound    = "siren"
+  priority = 2
+  retry    = 30
+  expire   = 600
+}`,
+Check: resource.ComposeTestCheckFunc(
+resource.TestCheckResourceAttrSet("pushover_message.page", "receipt"),
+resource.TestCheckResourceAttrSet("pushover_message.page", "request_id"),
+func(s *terraform.State) error {
+receipt := s.RootModule().Resources["pushover_message.page"].Primary.Attributes["receipt"]
+if _, err := srv.Receipt(receipt); err != nil {
+return fmt.Errorf("receipt %q not issued by the server: %w", receipt, err)
+}
+return nil
+},
+),
+},
+},
+})
+}
+
+// TestMessageResource_RejectedByFakeAPI checks that API validation errors
+// surface as diagnostics.
+func TestMessageResource_RejectedByFakeAPI(t *testing.T) {
+testAPI(t)
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {}
+
+resource "pushover_message" "bad" {
+  user_key = "` + os.Getenv("PUSHOVER_USER_KEY") + `"
+  message  = "hi"
+  sound    = "kazoo"
+}`,
+ExpectError: regexp.MustCompile(`sound is invalid`),
+},
+},
+})
+}
+
+// TestMessageResource_PartialDeliveryAgainstFakeAPI checks that a failed
+// batch keeps its position in request_ids and receipts.
+func TestMessageResource_PartialDeliveryAgainstFakeAPI(t *testing.T) {
+srv := testAPI(t)
+if srv == nil {
+t.Skip("needs 60 registered users; only runs against the fake API")
+}
+keys := make([]string, 60)
+for i := range keys {
+keys[i] = fmt.Sprintf("uPartialDelivery%014d", i)
+srv.AddUser(keys[i], "iphone")
+}
+srv.FailNext("messages.json", http.StatusBadRequest, "message rejected")
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {}
+
+resource "pushover_message" "all" {
+  user_keys = ["` + strings.Join(keys, `", "`) + `"]
+  message   = "hi"
+}`,
+ExpectError: regexp.MustCompile(`Message Partially Delivered`),
+},
+{
+// The partially delivered message is tainted, so it plans to be resent.
+RefreshState:       true,
+ExpectNonEmptyPlan: true,
+Check: resource.ComposeTestCheckFunc(
+resource.TestCheckResourceAttr("pushover_message.all", "request_ids.#", "2"),
+resource.TestCheckResourceAttr("pushover_message.all", "request_ids.0", ""),
+resource.TestCheckResourceAttrPair("pushover_message.all", "request_ids.1", "pushover_message.all", "request_id"),
+resource.TestCheckResourceAttr("pushover_message.all", "receipts.#", "2"),
+),
+},
+},
+})
+}
@@ -5,29 +5,163 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 
-	"github.com/Josh-Archer/terraform-provider-pushover/internal/pushover"
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure PushoverProvider satisfies various provider interfaces.
 var _ provider.Provider = &PushoverProvider{}
 var _ provider.ProviderWithFunctions = &PushoverProvider{}
+var _ provider.ProviderWithValidateConfig = &PushoverProvider{}
 
 // PushoverProvider defines the provider implementation.
 type PushoverProvider struct {
 	version string
+	// newClient, when set, replaces the real Pushover client, e.g. with an
+	// in-memory fake in tests.
+	newClient func(token string) pushover.API
 }
 
 // PushoverProviderModel describes the provider data model.
 type PushoverProviderModel struct {
-	APIToken types.String `tfsdk:"api_token"`
+	APIToken           types.String                                `tfsdk:"api_token"`
+	APITokenFile       types.String                                `tfsdk:"api_token_file"`
+	APITokenCommand    types.List                                  `tfsdk:"api_token_command"`
+	BaseURL            types.String                                `tfsdk:"base_url"`
+	RequestTimeout     types.String                                `tfsdk:"request_timeout"`
+	ProxyURL           types.String                                `tfsdk:"proxy_url"`
+	CACertPEM          types.String                                `tfsdk:"ca_cert_pem"`
+	CACertFile         types.String                                `tfsdk:"ca_cert_file"`
+	InsecureSkipVerify types.Bool                                  `tfsdk:"insecure_skip_verify"`
+	RateLimit          types.Float64                               `tfsdk:"rate_limit"`
+	RateLimitBurst     types.Int64                                 `tfsdk:"rate_limit_burst"`
+	Applications       map[string]PushoverProviderApplicationModel `tfsdk:"applications"`
+	Defaults           *PushoverProviderDefaultsModel              `tfsdk:"defaults"`
+	Profiles           []PushoverProviderProfileModel              `tfsdk:"profile"`
+}
+
+// PushoverProviderApplicationModel describes an entry of the applications map.
+type PushoverProviderApplicationModel struct {
+	APIToken        types.String `tfsdk:"api_token"`
+	APITokenFile    types.String `tfsdk:"api_token_file"`
+	APITokenCommand types.List   `tfsdk:"api_token_command"`
+}
+
+// PushoverProviderDefaultsModel describes the defaults block of the provider.
+type PushoverProviderDefaultsModel struct {
+	UserKey     types.String `tfsdk:"user_key"`
+	Sound       types.String `tfsdk:"sound"`
+	TitlePrefix types.String `tfsdk:"title_prefix"`
+	Device      types.String `tfsdk:"device"`
+	Retry       types.Int64  `tfsdk:"retry"`
+	Expire      types.Int64  `tfsdk:"expire"`
+}
+
+// PushoverProviderProfileModel describes a profile block of the provider.
+type PushoverProviderProfileModel struct {
+	Name     types.String `tfsdk:"name"`
+	Priority types.Int64  `tfsdk:"priority"`
+	Sound    types.String `tfsdk:"sound"`
+	Device   types.String `tfsdk:"device"`
+	Retry    types.Int64  `tfsdk:"retry"`
+	Expire   types.Int64  `tfsdk:"expire"`
+}
+
+// providerData is passed to resources and data sources from Configure.
+type providerData struct {
+	clients  *clientSet
+	defaults messageDefaults
+	profiles map[string]messageProfile
+}
+
+// clientSet holds the client for the provider-level api_token and one client
+// per entry of the applications map. newClient creates clients that share the
+// provider's endpoint and transport settings.
+type clientSet struct {
+	defaultClient pushover.API
+	applications  map[string]pushover.API
+	newClient     func(token string) pushover.API
+}
+
+// get returns the client for the named application, or the default client
+// when application is null.
+func (c *clientSet) get(application types.String) (pushover.API, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if application.IsNull() {
+		if c.defaultClient == nil {
+			diags.AddAttributeError(
+				path.Root("application"),
+				"Missing Application",
+				"The provider has no api_token configured, so application must be set to one of the names in the provider's applications map.",
+			)
+		}
+		return c.defaultClient, diags
+	}
+
+	name := application.ValueString()
+	client, ok := c.applications[name]
+	if !ok {
+		names := make([]string, 0, len(c.applications))
+		for n := range c.applications {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		diags.AddAttributeError(
+			path.Root("application"),
+			"Unknown Application",
+			fmt.Sprintf("No application named %q is defined in the provider's applications map. Defined applications: [%s].", name, strings.Join(names, ", ")),
+		)
+	}
+	return client, diags
+}
+
+// getWithOverride is like get, but when no application is selected and
+// apiToken holds a per-request token override, it returns a client for that
+// token. A selected application always wins, and its errors are reported.
+func (c *clientSet) getWithOverride(application, apiToken types.String) (pushover.API, diag.Diagnostics) {
+	if application.IsNull() && !apiToken.IsNull() && !apiToken.IsUnknown() {
+		return c.newClient(apiToken.ValueString()), nil
+	}
+	return c.get(application)
+}
+
+// messageDefaults holds provider-level values that pushover_message uses
+// when the resource leaves the corresponding attribute unset.
+type messageDefaults struct {
+	UserKey     string
+	Sound       string
+	TitlePrefix string
+	Device      string
+	Retry       int64
+	Expire      int64
+}
+
+// messageProfile holds the settings of a named profile block. Zero values
+// mean the profile leaves the setting to the provider defaults.
+type messageProfile struct {
+	Priority int64
+	Sound    string
+	Device   string
+	Retry    int64
+	Expire   int64
 }
 
 // New creates a new instance of the Pushover provider.
@@ -39,6 +173,18 @@ func New(version string) func() provider.Provider {
 	}
 }
 
+// NewWithClientFactory creates a provider whose resources and data sources
+// call the clients returned by newClient, one per configured API token,
+// instead of the Pushover API. It lets resources be unit-tested against fakes.
+func NewWithClientFactory(version string, newClient func(token string) pushover.API) func() provider.Provider {
+	return func() provider.Provider {
+		return &PushoverProvider{
+			version:   version,
+			newClient: newClient,
+		}
+	}
+}
+
 func (p *PushoverProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "pushover"
 	resp.Version = p.version
@@ -51,44 +197,393 @@ func (p *PushoverProvider) Schema(_ context.Context, _ provider.SchemaRequest, r
 		Attributes: map[string]schema.Attribute{
 			"api_token": schema.StringAttribute{
 				MarkdownDescription: "The Pushover application API token. " +
-					"Can also be set via the `PUSHOVER_API_TOKEN` environment variable.",
+					"Can also be set via the `PUSHOVER_API_TOKEN` environment variable. " +
+					"Conflicts with `api_token_file` and `api_token_command`.",
 				Optional:  true,
 				Sensitive: true,
 			},
+			"api_token_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file holding the API token. Surrounding whitespace is trimmed. " +
+					"A warning is raised when the file is accessible by other users. Conflicts with `api_token` and `api_token_command`.",
+				Optional: true,
+			},
+			"api_token_command": schema.ListAttribute{
+				MarkdownDescription: "An executable and its arguments that print the API token on stdout, such as a credential helper. " +
+					"The command is run without a shell and must finish within 30 seconds. Conflicts with `api_token` and `api_token_file`.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"base_url": schema.StringAttribute{
+				MarkdownDescription: "The Pushover API endpoint, for example a mock server used in CI. " +
+					"Can also be set via the `PUSHOVER_BASE_URL` environment variable. Defaults to `https://api.pushover.net/1`.",
+				Optional: true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "How long a single API request may take, as a duration such as `\"10s\"` or `\"1m\"`. Time spent waiting for `rate_limit` is not included. Defaults to `\"30s\"`.",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "An HTTP or HTTPS proxy to route API requests through. " +
+					"Defaults to the `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables.",
+				Optional: true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded CA certificates trusted in addition to the system roots, such as those of a TLS-intercepting proxy. " +
+					"Conflicts with `ca_cert_file`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("ca_cert_file")),
+				},
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file of PEM-encoded CA certificates trusted in addition to the system roots. Conflicts with `ca_cert_pem`.",
+				Optional:            true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Disable TLS certificate verification. Intended for local mock servers only. Defaults to `false`.",
+				Optional:            true,
+			},
+			"rate_limit": schema.Float64Attribute{
+				MarkdownDescription: "The average number of API requests per second, shared by every resource and data source using this provider configuration. " +
+					"Requests over the limit wait for their turn instead of failing. Unset or `0` sends requests without a limit.",
+				Optional: true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"rate_limit_burst": schema.Int64Attribute{
+				MarkdownDescription: "The number of API requests that may be sent at once before `rate_limit` applies. Only used with `rate_limit`. Defaults to `5`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"applications": schema.MapNestedAttribute{
+				MarkdownDescription: "Additional Pushover applications, keyed by a name of your choosing. " +
+					"Resources and data sources select one with their `application` attribute; those that leave it unset use `api_token`. " +
+					"When this map is set, `api_token` becomes optional. Each entry sets exactly one of `api_token`, `api_token_file` or `api_token_command`.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"api_token": schema.StringAttribute{
+							MarkdownDescription: "The application's API token.",
+							Optional:            true,
+							Sensitive:           true,
+						},
+						"api_token_file": schema.StringAttribute{
+							MarkdownDescription: "Path to a file holding the application's API token.",
+							Optional:            true,
+						},
+						"api_token_command": schema.ListAttribute{
+							MarkdownDescription: "An executable and its arguments that print the application's API token on stdout.",
+							Optional:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"defaults": schema.SingleNestedBlock{
+				MarkdownDescription: "Values used by `pushover_message` when the resource leaves the corresponding attribute unset. " +
+					"Each value can also be set via an environment variable.",
+				Attributes: map[string]schema.Attribute{
+					"user_key": schema.StringAttribute{
+						MarkdownDescription: "Default recipient user or group key. Can also be set via the `PUSHOVER_USER_KEY` environment variable.",
+						Optional:            true,
+						Sensitive:           true,
+						Validators: []validator.String{
+							userKeyValidator(),
+						},
+					},
+					"sound": schema.StringAttribute{
+						MarkdownDescription: "Default notification sound. Can also be set via the `PUSHOVER_SOUND` environment variable.",
+						Optional:            true,
+						Validators: []validator.String{
+							soundValidator(),
+						},
+					},
+					"title_prefix": schema.StringAttribute{
+						MarkdownDescription: "Text prepended to every message title. Can also be set via the `PUSHOVER_TITLE_PREFIX` environment variable.",
+						Optional:            true,
+					},
+					"device": schema.StringAttribute{
+						MarkdownDescription: "Default target device. Can also be set via the `PUSHOVER_DEVICE` environment variable.",
+						Optional:            true,
+						Validators: []validator.String{
+							deviceListValidator(),
+						},
+					},
+					"retry": schema.Int64Attribute{
+						MarkdownDescription: "Default retry interval in seconds for emergency messages. Minimum: 30. Can also be set via the `PUSHOVER_RETRY` environment variable.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(30),
+						},
+					},
+					"expire": schema.Int64Attribute{
+						MarkdownDescription: "Default expiry in seconds for emergency messages. Maximum: 10800. Can also be set via the `PUSHOVER_EXPIRE` environment variable.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.Between(1, 10800),
+						},
+					},
+				},
+			},
+			"profile": schema.ListNestedBlock{
+				MarkdownDescription: "A named set of message settings, such as a severity preset, that `pushover_message` selects with its `profile` attribute. " +
+					"Attributes set on the resource override the profile, and the profile overrides `defaults`.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The profile name referenced by `pushover_message.profile`. Must be unique.",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"priority": schema.Int64Attribute{
+							MarkdownDescription: "Message priority, from `-2` to `2`.",
+							Optional:            true,
+							Validators: []validator.Int64{
+								int64validator.Between(-2, 2),
+							},
+						},
+						"sound": schema.StringAttribute{
+							MarkdownDescription: "Notification sound.",
+							Optional:            true,
+							Validators: []validator.String{
+								soundValidator(),
+							},
+						},
+						"device": schema.StringAttribute{
+							MarkdownDescription: "Target device.",
+							Optional:            true,
+							Validators: []validator.String{
+								deviceListValidator(),
+							},
+						},
+						"retry": schema.Int64Attribute{
+							MarkdownDescription: "Retry interval in seconds for emergency messages. Minimum: 30.",
+							Optional:            true,
+							Validators: []validator.Int64{
+								int64validator.AtLeast(30),
+							},
+						},
+						"expire": schema.Int64Attribute{
+							MarkdownDescription: "Expiry in seconds for emergency messages. Maximum: 10800.",
+							Optional:            true,
+							Validators: []validator.Int64{
+								int64validator.Between(1, 10800),
+							},
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
-func (p *PushoverProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+func (p *PushoverProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
 	var data PushoverProviderModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	apiToken := os.Getenv("PUSHOVER_API_TOKEN")
-	if !data.APIToken.IsNull() && !data.APIToken.IsUnknown() {
-		apiToken = data.APIToken.ValueString()
+	resp.Diagnostics.Append(data.tokenSource().validate(ctx)...)
+	resp.Diagnostics.Append(data.validateTransport()...)
+	for name, app := range data.Applications {
+		src := app.tokenSource(name)
+		resp.Diagnostics.Append(src.validate(ctx)...)
+		if len(src.configured()) == 0 {
+			resp.Diagnostics.AddAttributeError(
+				src.base,
+				"Missing Application API Token",
+				fmt.Sprintf("Application %q must set one of api_token, api_token_file or api_token_command.", name),
+			)
+		}
 	}
 
+	seen := make(map[string]bool, len(data.Profiles))
+	for i, pm := range data.Profiles {
+		if pm.Name.IsUnknown() || pm.Name.IsNull() {
+			continue
+		}
+		name := pm.Name.ValueString()
+		if seen[name] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("profile").AtListIndex(i).AtName("name"),
+				"Duplicate Message Profile",
+				fmt.Sprintf("A profile named %q is already defined. Profile names must be unique.", name),
+			)
+		}
+		seen[name] = true
+	}
+}
+
+// tokenSource returns the provider-level token attributes.
+func (m PushoverProviderModel) tokenSource() tokenSource {
+	return tokenSource{
+		base:    path.Empty(),
+		token:   m.APIToken,
+		file:    m.APITokenFile,
+		command: m.APITokenCommand,
+	}
+}
+
+// tokenSource returns the token attributes of the named applications entry.
+func (m PushoverProviderApplicationModel) tokenSource(name string) tokenSource {
+	return tokenSource{
+		base:    path.Root("applications").AtMapKey(name),
+		token:   m.APIToken,
+		file:    m.APITokenFile,
+		command: m.APITokenCommand,
+	}
+}
+
+func (p *PushoverProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var data PushoverProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiToken, diags := data.tokenSource().resolve(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	if apiToken == "" {
+		apiToken = os.Getenv("PUSHOVER_API_TOKEN")
+	}
+
+	if apiToken == "" && len(data.Applications) == 0 {
 		resp.Diagnostics.AddError(
 			"Missing API Token",
 			"The provider requires a Pushover application API token. "+
-				"Set the api_token attribute or the PUSHOVER_API_TOKEN environment variable.",
+				"Set api_token, api_token_file, api_token_command, the PUSHOVER_API_TOKEN environment variable, or the applications map.",
 		)
 		return
 	}
 
-	client := pushover.NewClient(apiToken)
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	baseURL, httpClient, diags := data.transport()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	opts := []pushover.Option{
+		pushover.WithBaseURL(baseURL),
+		pushover.WithHTTPClient(httpClient),
+		pushover.WithUserAgent("terraform-provider-pushover/" + p.version),
+		pushover.WithLogger(tflogLogger{}),
+	}
+	if limiter := data.rateLimiter(); limiter != nil {
+		// One limiter for every client, so all resources share the budget.
+		opts = append(opts, pushover.WithRateLimiter(limiter))
+	}
+
+	clients := &clientSet{
+		applications: make(map[string]pushover.API, len(data.Applications)),
+		newClient: func(token string) pushover.API {
+			return pushover.New(token, opts...)
+		},
+	}
+	if p.newClient != nil {
+		clients.newClient = p.newClient
+	}
+	if apiToken != "" {
+		clients.defaultClient = clients.newClient(apiToken)
+	}
+	for name, app := range data.Applications {
+		token, diags := app.tokenSource(name).resolve(ctx)
+		resp.Diagnostics.Append(diags...)
+		clients.applications[name] = clients.newClient(token)
+	}
+
+	defaults := messageDefaults{
+		UserKey:     os.Getenv("PUSHOVER_USER_KEY"),
+		Sound:       os.Getenv("PUSHOVER_SOUND"),
+		TitlePrefix: os.Getenv("PUSHOVER_TITLE_PREFIX"),
+		Device:      os.Getenv("PUSHOVER_DEVICE"),
+	}
+	for env, dst := range map[string]*int64{"PUSHOVER_RETRY": &defaults.Retry, "PUSHOVER_EXPIRE": &defaults.Expire} {
+		if v := os.Getenv(env); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Invalid Environment Variable",
+					fmt.Sprintf("The %s environment variable must be a whole number of seconds, got %q.", env, v),
+				)
+				continue
+			}
+			*dst = n
+		}
+	}
+	if d := data.Defaults; d != nil {
+		setIfKnown(&defaults.UserKey, d.UserKey)
+		setIfKnown(&defaults.Sound, d.Sound)
+		setIfKnown(&defaults.TitlePrefix, d.TitlePrefix)
+		setIfKnown(&defaults.Device, d.Device)
+		if !d.Retry.IsNull() && !d.Retry.IsUnknown() {
+			defaults.Retry = d.Retry.ValueInt64()
+		}
+		if !d.Expire.IsNull() && !d.Expire.IsUnknown() {
+			defaults.Expire = d.Expire.ValueInt64()
+		}
+	}
+
+	// ValidateConfig has rejected duplicate profile names.
+	profiles := make(map[string]messageProfile, len(data.Profiles))
+	for _, pm := range data.Profiles {
+		profiles[pm.Name.ValueString()] = messageProfile{
+			Priority: pm.Priority.ValueInt64(),
+			Sound:    pm.Sound.ValueString(),
+			Device:   pm.Device.ValueString(),
+			Retry:    pm.Retry.ValueInt64(),
+			Expire:   pm.Expire.ValueInt64(),
+		}
+	}
+
+	pd := &providerData{
+		clients:  clients,
+		defaults: defaults,
+		profiles: profiles,
+	}
+	resp.DataSourceData = pd
+	resp.ResourceData = pd
+}
+
+// setIfKnown overwrites dst with v when v holds a configured value.
+func setIfKnown(dst *string, v types.String) {
+	if !v.IsNull() && !v.IsUnknown() {
+		*dst = v.ValueString()
+	}
+}
+
+// addClientError reports an error returned by the Pushover client. A
+// *pushover.ValidationError is attached to the attribute in attrs that
+// supplied the rejected argument, keyed by the API parameter name.
+func addClientError(diags *diag.Diagnostics, summary string, err error, attrs map[string]path.Path) {
+	var vErr *pushover.ValidationError
+	if errors.As(err, &vErr) {
+		if attrPath, ok := attrs[vErr.Field]; ok {
+			diags.AddAttributeError(attrPath, summary, err.Error())
+			return
+		}
+	}
+	diags.AddError(summary, err.Error())
 }
 
 func (p *PushoverProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewMessageResource,
 		NewGroupUserResource,
+		NewGlanceResource,
+		NewSubscriptionUserResource,
+		NewTeamMemberResource,
+		NewLicenseAssignmentResource,
 	}
 }
 
@@ -96,6 +591,9 @@ func (p *PushoverProvider) DataSources(_ context.Context) []func() datasource.Da
 	return []func() datasource.DataSource{
 		NewSoundsDataSource,
 		NewValidateUserDataSource,
+		NewGroupHealthDataSource,
+		NewTeamDataSource,
+		NewLicenseCreditsDataSource,
 	}
 }
 
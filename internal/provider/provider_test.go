@@ -4,15 +4,26 @@
 package provider_test
 
 import (
+"fmt"
+"net/http"
+"net/http/httptest"
 "os"
+"path/filepath"
 "regexp"
+"runtime"
 "testing"
 
 "github.com/hashicorp/terraform-plugin-framework/providerserver"
 "github.com/hashicorp/terraform-plugin-go/tfprotov6"
 "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+"github.com/hashicorp/terraform-plugin-testing/plancheck"
+"github.com/hashicorp/terraform-plugin-testing/terraform"
+"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 
 "github.com/Josh-Archer/terraform-provider-pushover/internal/provider"
+"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -22,13 +33,81 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 "pushover": providerserver.NewProtocol6WithError(provider.New("test")()),
 }
 
-// skipIfNoToken skips the test when PUSHOVER_API_TOKEN is not set.
-// Acceptance tests that make real API calls must call this helper.
-func skipIfNoToken(t *testing.T) {
+// testAPI points the provider at an in-process pushovertest server and sets
+// PUSHOVER_API_TOKEN, PUSHOVER_USER_KEY, PUSHOVER_GROUP_KEY,
+// PUSHOVER_SUBSCRIPTION_CODE, PUSHOVER_TEAM_TOKEN and PUSHOVER_LICENSE_EMAIL
+// to its seeded credentials, so tests that call the API run offline. When TF_ACC and
+// PUSHOVER_API_TOKEN are both set, the real API is used instead and nil is
+// returned.
+func testAPI(t *testing.T) *pushovertest.Server {
 t.Helper()
-if os.Getenv("PUSHOVER_API_TOKEN") == "" {
-t.Skip("PUSHOVER_API_TOKEN not set; skipping acceptance test")
+if os.Getenv("TF_ACC") != "" && os.Getenv("PUSHOVER_API_TOKEN") != "" {
+return nil
 }
+srv := pushovertest.NewServer()
+t.Cleanup(srv.Close)
+t.Setenv("PUSHOVER_BASE_URL", srv.URL)
+t.Setenv("PUSHOVER_API_TOKEN", pushovertest.Token)
+t.Setenv("PUSHOVER_USER_KEY", pushovertest.UserKey)
+t.Setenv("PUSHOVER_GROUP_KEY", pushovertest.GroupKey)
+t.Setenv("PUSHOVER_SUBSCRIPTION_CODE", pushovertest.SubscriptionCode)
+t.Setenv("PUSHOVER_TEAM_TOKEN", pushovertest.TeamToken)
+t.Setenv("PUSHOVER_LICENSE_EMAIL", "ada@example.com")
+return srv
+}
+
+// testEnv returns the environment variable name, which testAPI sets for the
+// fake API. Against the real API, the test is skipped when it is unset.
+func testEnv(t *testing.T, name string) string {
+t.Helper()
+v := os.Getenv(name)
+if v == "" {
+t.Skipf("set %s to run this test against the real API", name)
+}
+return v
+}
+
+// onFake returns check when srv is the fake API, and a check that does
+// nothing when the tests run against the real API, whose state they cannot
+// inspect.
+func onFake(srv *pushovertest.Server, check resource.TestCheckFunc) resource.TestCheckFunc {
+if srv == nil {
+return func(*terraform.State) error { return nil }
+}
+return check
+}
+
+// replayProviderFactories returns provider factories whose clients answer
+// every request from the named fixture in testdata/fixtures, in the format
+// of pushovertest.Recorder. The checked-in fixture is synthetic, written by
+// hand rather than recorded. Set PUSHOVER_RECORD=1 together with
+// PUSHOVER_API_TOKEN and PUSHOVER_USER_KEY to record it from the real API.
+func replayProviderFactories(t *testing.T, fixture string) map[string]func() (tfprotov6.ProviderServer, error) {
+t.Helper()
+rec, err := pushovertest.NewRecorder(filepath.Join("testdata", "fixtures", fixture+".json"), nil)
+if err != nil {
+t.Fatal(err)
+}
+t.Cleanup(func() {
+if err := rec.Save(); err != nil {
+t.Error(err)
+}
+})
+newClient := func(token string) pushover.API {
+return pushover.New(token, pushover.WithHTTPClient(&http.Client{Transport: rec}))
+}
+return map[string]func() (tfprotov6.ProviderServer, error){
+"pushover": providerserver.NewProtocol6WithError(provider.NewWithClientFactory("test", newClient)()),
+}
+}
+
+// replayKey returns the environment variable env while recording, and a
+// well-formed placeholder key otherwise.
+func replayKey(env, placeholder string) string {
+if os.Getenv(pushovertest.RecordEnv) != "" {
+return os.Getenv(env)
+}
+return placeholder
 }
 
 // ----- Provider configuration -----
@@ -44,7 +123,7 @@ Config: `
 provider "pushover" { api_token = "test_token_abc123" }
 
 resource "pushover_message" "probe" {
-  user_key = "uABC"
+  user_key = "uABCdefghijklmnopqrstuvwxyz123"
   message  = "probe"
 }`,
 PlanOnly:           true,
@@ -65,7 +144,7 @@ Config: `
 provider "pushover" {}
 
 resource "pushover_message" "probe" {
-  user_key = "uABC"
+  user_key = "uABCdefghijklmnopqrstuvwxyz123"
   message  = "probe"
 }`,
 PlanOnly:    true,
@@ -87,7 +166,7 @@ Config: `
 provider "pushover" { api_token = "tok" }
 
 resource "pushover_message" "probe" {
-  user_key = "uABC"
+  user_key = "uABCdefghijklmnopqrstuvwxyz123"
   message  = "probe"
 }`,
 PlanOnly:           true,
@@ -107,8 +186,8 @@ Config: `
 provider "pushover" { api_token = "tok" }
 
 resource "pushover_group_user" "probe" {
-  group_key = "gABC"
-  user_key  = "uABC"
+  group_key = "gABCdefghijklmnopqrstuvwxyz123"
+  user_key  = "uABCdefghijklmnopqrstuvwxyz123"
 }`,
 PlanOnly:           true,
 ExpectNonEmptyPlan: true,
@@ -117,11 +196,11 @@ ExpectNonEmptyPlan: true,
 })
 }
 
-// ----- Data source presence (acceptance; skipped when no API token) -----
+// ----- Data source presence (fake API unless TF_ACC is set) -----
 
 func TestProvider_HasSoundsDataSource(t *testing.T) {
-skipIfNoToken(t)
-resource.Test(t, resource.TestCase{
+testAPI(t)
+resource.UnitTest(t, resource.TestCase{
 ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 Steps: []resource.TestStep{
 {
@@ -137,12 +216,12 @@ resource.TestCheckResourceAttrSet("data.pushover_sounds.all", "id"),
 }
 
 func TestProvider_HasValidateUserDataSource(t *testing.T) {
-skipIfNoToken(t)
+testAPI(t)
 userKey := os.Getenv("PUSHOVER_USER_KEY")
 if userKey == "" {
 t.Skip("PUSHOVER_USER_KEY not set; skipping acceptance test")
 }
-resource.Test(t, resource.TestCase{
+resource.UnitTest(t, resource.TestCase{
 ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 Steps: []resource.TestStep{
 {
@@ -159,3 +238,489 @@ resource.TestCheckResourceAttrSet("data.pushover_validate_user.check", "id"),
 },
 })
 }
+
+// ----- Message defaults -----
+
+// TestProvider_DefaultsFillMessage validates that provider defaults appear in the plan.
+func TestProvider_DefaultsFillMessage(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  api_token = "tok"
+  defaults {
+    user_key     = "uDefaultUserKey000000000000000"
+    sound        = "siren"
+    title_prefix = "[prod] "
+    device       = "pager"
+    retry        = 60
+    expire       = 3600
+  }
+}
+
+resource "pushover_message" "probe" {
+  message  = "probe"
+  title    = "Disk full"
+  priority = 2
+}`,
+PlanOnly:           true,
+ExpectNonEmptyPlan: true,
+ConfigPlanChecks: resource.ConfigPlanChecks{
+PostApplyPreRefresh: []plancheck.PlanCheck{
+plancheck.ExpectKnownValue("pushover_message.probe", tfjsonpath.New("user_key"), knownvalue.StringExact("uDefaultUserKey000000000000000")),
+plancheck.ExpectKnownValue("pushover_message.probe", tfjsonpath.New("sound"), knownvalue.StringExact("siren")),
+plancheck.ExpectKnownValue("pushover_message.probe", tfjsonpath.New("device"), knownvalue.StringExact("pager")),
+plancheck.ExpectKnownValue("pushover_message.probe", tfjsonpath.New("effective_title"), knownvalue.StringExact("[prod] Disk full")),
+plancheck.ExpectKnownValue("pushover_message.probe", tfjsonpath.New("retry"), knownvalue.Int64Exact(60)),
+plancheck.ExpectKnownValue("pushover_message.probe", tfjsonpath.New("expire"), knownvalue.Int64Exact(3600)),
+},
+},
+},
+},
+})
+}
+
+// TestProvider_DefaultsOverriddenByResource validates that resource attributes win over defaults.
+func TestProvider_DefaultsOverriddenByResource(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  api_token = "tok"
+  defaults {
+    user_key = "uDefaultUserKey000000000000000"
+    sound    = "siren"
+    retry    = 60
+  }
+}
+
+resource "pushover_message" "probe" {
+  user_key = "uExplicitUserKey00000000000000"
+  message  = "probe"
+  sound    = "magic"
+}`,
+PlanOnly:           true,
+ExpectNonEmptyPlan: true,
+ConfigPlanChecks: resource.ConfigPlanChecks{
+PostApplyPreRefresh: []plancheck.PlanCheck{
+plancheck.ExpectKnownValue("pushover_message.probe", tfjsonpath.New("user_key"), knownvalue.StringExact("uExplicitUserKey00000000000000")),
+plancheck.ExpectKnownValue("pushover_message.probe", tfjsonpath.New("sound"), knownvalue.StringExact("magic")),
+// Emergency defaults are not applied to non-emergency messages.
+plancheck.ExpectKnownValue("pushover_message.probe", tfjsonpath.New("retry"), knownvalue.Null()),
+},
+},
+},
+},
+})
+}
+
+// TestProvider_ChangedDefaultResendsMessage validates that changing an
+// emergency default replaces messages that rely on it.
+func TestProvider_ChangedDefaultResendsMessage(t *testing.T) {
+if testAPI(t) == nil {
+t.Skip("sends emergency messages; only runs against the fake API")
+}
+config := func(retry int) string {
+return fmt.Sprintf(`
+provider "pushover" {
+  defaults {
+    retry = %d
+  }
+}
+
+resource "pushover_message" "page" {
+  message  = "Disk full"
+  priority = 2
+  expire   = 600
+}`, retry)
+}
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: config(60),
+},
+{
+Config: config(120),
+ConfigPlanChecks: resource.ConfigPlanChecks{
+PreApply: []plancheck.PlanCheck{
+plancheck.ExpectResourceAction("pushover_message.page", plancheck.ResourceActionReplace),
+},
+},
+Check: resource.TestCheckResourceAttr("pushover_message.page", "retry", "120"),
+},
+},
+})
+}
+
+// TestProvider_DefaultUserKeyFromEnv validates that PUSHOVER_USER_KEY makes user_key optional.
+func TestProvider_DefaultUserKeyFromEnv(t *testing.T) {
+t.Setenv("PUSHOVER_USER_KEY", "uEnvUserKey0000000000000000000")
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "tok" }
+
+resource "pushover_message" "probe" {
+  message = "probe"
+}`,
+PlanOnly:           true,
+ExpectNonEmptyPlan: true,
+ConfigPlanChecks: resource.ConfigPlanChecks{
+PostApplyPreRefresh: []plancheck.PlanCheck{
+plancheck.ExpectKnownValue("pushover_message.probe", tfjsonpath.New("user_key"), knownvalue.StringExact("uEnvUserKey0000000000000000000")),
+},
+},
+},
+},
+})
+}
+
+// TestProvider_MissingRecipientWithoutDefault expects an error when no recipient can be resolved.
+func TestProvider_MissingRecipientWithoutDefault(t *testing.T) {
+t.Setenv("PUSHOVER_USER_KEY", "")
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token = "tok" }
+
+resource "pushover_message" "probe" {
+  message = "probe"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)missing recipient`),
+},
+},
+})
+}
+
+// ----- Multiple applications -----
+
+// TestProvider_ApplicationsWithoutDefaultToken validates that the applications map replaces api_token.
+func TestProvider_ApplicationsWithoutDefaultToken(t *testing.T) {
+t.Setenv("PUSHOVER_API_TOKEN", "")
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  applications = {
+    alerts  = { api_token = "alerts_token" }
+    deploys = { api_token = "deploys_token" }
+  }
+}
+
+resource "pushover_message" "probe" {
+  user_key    = "uABCdefghijklmnopqrstuvwxyz123"
+  message     = "probe"
+  application = "alerts"
+}
+
+resource "pushover_group_user" "probe" {
+  group_key   = "gABCdefghijklmnopqrstuvwxyz123"
+  user_key    = "uABCdefghijklmnopqrstuvwxyz123"
+  application = "deploys"
+}`,
+PlanOnly:           true,
+ExpectNonEmptyPlan: true,
+},
+},
+})
+}
+
+// TestProvider_UnknownApplication expects an error for an application missing from the map.
+func TestProvider_UnknownApplication(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  api_token    = "tok"
+  applications = {
+    alerts = { api_token = "alerts_token" }
+  }
+}
+
+resource "pushover_message" "probe" {
+  user_key    = "uABCdefghijklmnopqrstuvwxyz123"
+  message     = "probe"
+  application = "alrets"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)unknown application`),
+},
+},
+})
+}
+
+// TestProvider_UnknownApplicationWithTokenOverride expects the unknown
+// application error even when the resource also overrides api_token.
+func TestProvider_UnknownApplicationWithTokenOverride(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  applications = {
+    alerts = { api_token = "alerts_token" }
+  }
+}
+
+resource "pushover_message" "probe" {
+  user_key    = "uABCdefghijklmnopqrstuvwxyz123"
+  message     = "probe"
+  application = "alrets"
+  api_token   = "override_token"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)unknown application`),
+},
+},
+})
+}
+
+// TestProvider_MissingApplication expects an error when no application is selected and there is no default token.
+func TestProvider_MissingApplication(t *testing.T) {
+t.Setenv("PUSHOVER_API_TOKEN", "")
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  applications = {
+    alerts = { api_token = "alerts_token" }
+  }
+}
+
+resource "pushover_message" "probe" {
+  user_key = "uABCdefghijklmnopqrstuvwxyz123"
+  message  = "probe"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)missing application`),
+},
+},
+})
+}
+
+// ----- API token sources -----
+
+// TestProvider_APITokenFile validates that the token can be read from a file.
+func TestProvider_APITokenFile(t *testing.T) {
+t.Parallel()
+tokenFile := filepath.Join(t.TempDir(), "token")
+if err := os.WriteFile(tokenFile, []byte("file_token\n"), 0o600); err != nil {
+t.Fatal(err)
+}
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token_file = "` + filepath.ToSlash(tokenFile) + `" }
+
+resource "pushover_message" "probe" {
+  user_key = "uABCdefghijklmnopqrstuvwxyz123"
+  message  = "probe"
+}`,
+PlanOnly:           true,
+ExpectNonEmptyPlan: true,
+},
+},
+})
+}
+
+// TestProvider_APITokenFileMissing expects an error for a token file that does not exist.
+func TestProvider_APITokenFileMissing(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token_file = "` + filepath.ToSlash(filepath.Join(t.TempDir(), "missing")) + `" }
+
+resource "pushover_message" "probe" {
+  user_key = "uABCdefghijklmnopqrstuvwxyz123"
+  message  = "probe"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)unable to read api token file`),
+},
+},
+})
+}
+
+// TestProvider_APITokenCommand validates that the token can be read from a command.
+func TestProvider_APITokenCommand(t *testing.T) {
+if runtime.GOOS == "windows" {
+t.Skip("uses a POSIX shell")
+}
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" { api_token_command = ["sh", "-c", "echo command_token"] }
+
+resource "pushover_message" "probe" {
+  user_key = "uABCdefghijklmnopqrstuvwxyz123"
+  message  = "probe"
+}`,
+PlanOnly:           true,
+ExpectNonEmptyPlan: true,
+},
+},
+})
+}
+
+// TestProvider_ConflictingTokenSources expects a validation error when several token sources are set.
+func TestProvider_ConflictingTokenSources(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  api_token         = "tok"
+  api_token_command = ["cat", "/run/secrets/pushover"]
+}
+
+resource "pushover_message" "probe" {
+  user_key = "uABCdefghijklmnopqrstuvwxyz123"
+  message  = "probe"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)conflicting api token sources`),
+},
+},
+})
+}
+
+// TestProvider_ApplicationWithoutToken expects a validation error for an application with no token source.
+func TestProvider_ApplicationWithoutToken(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  api_token    = "tok"
+  applications = {
+    alerts = {}
+  }
+}
+
+resource "pushover_message" "probe" {
+  user_key = "uABCdefghijklmnopqrstuvwxyz123"
+  message  = "probe"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)missing application api token`),
+},
+},
+})
+}
+
+// ----- Endpoint and transport -----
+
+// TestProvider_BaseURL validates that API requests are sent to base_url.
+func TestProvider_BaseURL(t *testing.T) {
+t.Parallel()
+srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+if r.URL.Path != "/1/sounds.json" {
+http.NotFound(w, r)
+return
+}
+w.Header().Set("Content-Type", "application/json")
+_, _ = w.Write([]byte(`{"status":1,"request":"r","sounds":{"magic":"Magic"}}`))
+}))
+defer srv.Close()
+
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  api_token       = "tok"
+  base_url        = "` + srv.URL + `/1"
+  request_timeout = "5s"
+}
+
+data "pushover_sounds" "all" {}`,
+Check: resource.ComposeTestCheckFunc(
+resource.TestCheckResourceAttr("data.pushover_sounds.all", "sounds.magic", "Magic"),
+),
+},
+},
+})
+}
+
+// TestProvider_InvalidBaseURL expects a validation error for a base_url that is not an http(s) URL.
+func TestProvider_InvalidBaseURL(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  api_token = "tok"
+  base_url  = "api.pushover.net"
+}
+
+resource "pushover_message" "probe" {
+  user_key = "uABCdefghijklmnopqrstuvwxyz123"
+  message  = "probe"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)invalid url`),
+},
+},
+})
+}
+
+// TestProvider_ConflictingCACerts expects a validation error when both CA certificate attributes are set.
+func TestProvider_ConflictingCACerts(t *testing.T) {
+t.Parallel()
+resource.UnitTest(t, resource.TestCase{
+ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+Steps: []resource.TestStep{
+{
+Config: `
+provider "pushover" {
+  api_token    = "tok"
+  ca_cert_pem  = "-----BEGIN CERTIFICATE-----"
+  ca_cert_file = "/etc/ssl/corp.pem"
+}
+
+resource "pushover_message" "probe" {
+  user_key = "uABCdefghijklmnopqrstuvwxyz123"
+  message  = "probe"
+}`,
+PlanOnly:    true,
+ExpectError: regexp.MustCompile(`(?i)invalid attribute combination`),
+},
+},
+})
+}
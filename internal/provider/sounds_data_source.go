@@ -7,7 +7,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/Josh-Archer/terraform-provider-pushover/internal/pushover"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -24,14 +23,15 @@ func NewSoundsDataSource() datasource.DataSource {
 
 // SoundsDataSource defines the data source implementation.
 type SoundsDataSource struct {
-	client *pushover.Client
+	clients *clientSet
 }
 
 // SoundsDataSourceModel describes the data source data model.
 type SoundsDataSourceModel struct {
-	Sounds types.Map    `tfsdk:"sounds"`
-	Keys   types.List   `tfsdk:"keys"`
-	ID     types.String `tfsdk:"id"`
+	Application types.String `tfsdk:"application"`
+	Sounds      types.Map    `tfsdk:"sounds"`
+	Keys        types.List   `tfsdk:"keys"`
+	ID          types.String `tfsdk:"id"`
 }
 
 func (d *SoundsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -47,6 +47,10 @@ func (d *SoundsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				MarkdownDescription: "Placeholder identifier.",
 				Computed:            true,
 			},
+			"application": schema.StringAttribute{
+				MarkdownDescription: "The name of an entry in the provider's `applications` map whose API token is used to list sounds. Defaults to the provider-level `api_token`.",
+				Optional:            true,
+			},
 			"sounds": schema.MapAttribute{
 				MarkdownDescription: "A map of sound key to human-readable sound name.",
 				Computed:            true,
@@ -65,19 +69,31 @@ func (d *SoundsDataSource) Configure(_ context.Context, req datasource.Configure
 	if req.ProviderData == nil {
 		return
 	}
-	client, ok := req.ProviderData.(*pushover.Client)
+	pd, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *pushover.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
-	d.client = client
+	d.clients = pd.clients
 }
 
-func (d *SoundsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
-	sounds, err := d.client.GetSounds(ctx)
+func (d *SoundsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SoundsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := d.clients.get(data.Application)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sounds, err := client.GetSounds(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to fetch Pushover sounds", err.Error())
 		return
@@ -102,10 +118,8 @@ func (d *SoundsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, r
 		return
 	}
 
-	state := SoundsDataSourceModel{
-		Sounds: soundsTF,
-		Keys:   keysTF,
-		ID:     types.StringValue("sounds"),
-	}
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	data.Sounds = soundsTF
+	data.Keys = keysTF
+	data.ID = types.StringValue("sounds")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -0,0 +1,188 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SubscriptionUserResource{}
+
+// NewSubscriptionUserResource creates a new subscription user resource.
+func NewSubscriptionUserResource() resource.Resource {
+	return &SubscriptionUserResource{}
+}
+
+// SubscriptionUserResource migrates an existing user key to a Pushover
+// subscription.
+type SubscriptionUserResource struct {
+	clients *clientSet
+}
+
+// SubscriptionUserResourceModel describes the resource data model.
+type SubscriptionUserResourceModel struct {
+	UserKey          types.String `tfsdk:"user_key"`
+	SubscriptionCode types.String `tfsdk:"subscription_code"`
+	Device           types.String `tfsdk:"device"`
+	Sound            types.String `tfsdk:"sound"`
+	Application      types.String `tfsdk:"application"`
+
+	// Computed
+	ID                types.String `tfsdk:"id"`
+	SubscribedUserKey types.String `tfsdk:"subscribed_user_key"`
+}
+
+// subscriptionUserAttrs maps client validation errors to the attribute that supplied the value.
+var subscriptionUserAttrs = map[string]path.Path{
+	"subscription": path.Root("subscription_code"),
+	"user":         path.Root("user_key"),
+}
+
+func (r *SubscriptionUserResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_subscription_user"
+}
+
+func (r *SubscriptionUserResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	requiresReplace := []planmodifier.String{
+		stringplanmodifier.RequiresReplace(),
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Subscribes an existing Pushover user to one of your application's subscriptions, without the user having to open the subscription page. " +
+			"The resulting `subscribed_user_key` receives messages in place of the user's own key, for example in `pushover_message`. " +
+			"Pushover has no API to read or cancel subscriptions, so destroying this resource only removes it from state; the user can unsubscribe in the Pushover app.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Identifier of the migration (`subscription_code/user_key[/device]`).",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user_key": schema.StringAttribute{
+				MarkdownDescription: "The user's existing Pushover user key.",
+				Required:            true,
+				Validators: []validator.String{
+					userKeyValidator(),
+				},
+				PlanModifiers: requiresReplace,
+			},
+			"subscription_code": schema.StringAttribute{
+				MarkdownDescription: "The subscription code shown on the subscription's page in the Pushover dashboard.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: requiresReplace,
+			},
+			"device": schema.StringAttribute{
+				MarkdownDescription: "Subscribe only this one of the user's devices.",
+				Optional:            true,
+				Validators: []validator.String{
+					deviceNameValidator(),
+				},
+				PlanModifiers: requiresReplace,
+			},
+			"sound": schema.StringAttribute{
+				MarkdownDescription: "The sound the user hears for notifications from the subscription.",
+				Optional:            true,
+				PlanModifiers:       requiresReplace,
+				Validators: []validator.String{
+					soundValidator(),
+				},
+			},
+			"application": schema.StringAttribute{
+				MarkdownDescription: "The name of an entry in the provider's `applications` map for the application that owns the subscription. Defaults to the provider-level `api_token`.",
+				Optional:            true,
+				PlanModifiers:       requiresReplace,
+			},
+			"subscribed_user_key": schema.StringAttribute{
+				MarkdownDescription: "The subscription-specific user key. Send messages to it instead of `user_key`.",
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *SubscriptionUserResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.clients = pd.clients
+}
+
+func (r *SubscriptionUserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SubscriptionUserResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, diags := r.clients.get(data.Application)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	subscriptions, ok := client.(pushover.SubscriptionAPI)
+	if !ok {
+		resp.Diagnostics.AddError("Subscriptions Not Supported", fmt.Sprintf("The configured Pushover client (%T) cannot migrate subscriptions.", client))
+		return
+	}
+
+	result, err := subscriptions.MigrateSubscription(ctx, &pushover.SubscriptionMigrateRequest{
+		Subscription: data.SubscriptionCode.ValueString(),
+		User:         data.UserKey.ValueString(),
+		DeviceName:   data.Device.ValueString(),
+		Sound:        data.Sound.ValueString(),
+	})
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Failed to migrate user to subscription", err, subscriptionUserAttrs)
+		return
+	}
+
+	id := data.SubscriptionCode.ValueString() + "/" + data.UserKey.ValueString()
+	if !data.Device.IsNull() {
+		id += "/" + data.Device.ValueString()
+	}
+	data.ID = types.StringValue(id)
+	data.SubscribedUserKey = types.StringValue(result.SubscribedUserKey)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read does nothing since Pushover subscriptions cannot be retrieved.
+func (r *SubscriptionUserResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+}
+
+// Update is not used; all changes require replacement.
+func (r *SubscriptionUserResource) Update(_ context.Context, _ resource.UpdateRequest, _ *resource.UpdateResponse) {
+}
+
+// Delete does nothing since Pushover subscriptions cannot be canceled through the API.
+func (r *SubscriptionUserResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+}
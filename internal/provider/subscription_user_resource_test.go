@@ -0,0 +1,136 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider_test
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
+)
+
+// TestSubscriptionUserResource_BasicSchema validates the required and optional fields are accepted.
+func TestSubscriptionUserResource_BasicSchema(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_subscription_user" "alice" {
+  user_key          = "utest123456789abcdefghijklmnop"
+  subscription_code = "ops-alerts"
+  device            = "iphone"
+}`,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// TestSubscriptionUserResource_MigrateAndSend migrates a user and sends a
+// message to the subscribed key. Against the fake API it also binds the
+// subscription to one of the user's devices.
+func TestSubscriptionUserResource_MigrateAndSend(t *testing.T) {
+	srv := testAPI(t)
+	code := testEnv(t, "PUSHOVER_SUBSCRIPTION_CODE")
+	userKey := os.Getenv("PUSHOVER_USER_KEY")
+	id, device := code+"/"+userKey, ""
+	if srv != nil {
+		id, device = code+"/"+userKey+"/pixel", `device            = "pixel"`
+	}
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pushover" {}
+
+resource "pushover_subscription_user" "alice" {
+  user_key          = "` + userKey + `"
+  subscription_code = "` + code + `"
+  ` + device + `
+  sound             = "bugle"
+}
+
+resource "pushover_message" "welcome" {
+  user_key = pushover_subscription_user.alice.subscribed_user_key
+  message  = "Welcome to ops alerts"
+}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pushover_subscription_user.alice", "id", id),
+					resource.TestCheckResourceAttrSet("pushover_subscription_user.alice", "subscribed_user_key"),
+					resource.TestCheckResourceAttrPair("pushover_message.welcome", "user_key", "pushover_subscription_user.alice", "subscribed_user_key"),
+					onFake(srv, func(s *terraform.State) error {
+						subscribed := s.RootModule().Resources["pushover_subscription_user.alice"].Primary.Attributes["subscribed_user_key"]
+						sub, err := srv.Subscriber(subscribed)
+						if err != nil {
+							return fmt.Errorf("subscribed key %q: %w", subscribed, err)
+						}
+						if sub.User != pushovertest.UserKey || sub.Device != "pixel" || sub.Sound != "bugle" {
+							return fmt.Errorf("unexpected subscriber %+v", sub)
+						}
+						msgs := srv.Messages()
+						if len(msgs) != 1 || len(msgs[0].Users) != 1 || msgs[0].Users[0] != subscribed {
+							return fmt.Errorf("expected one message to the subscribed key, got %+v", msgs)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+// TestSubscriptionUserResource_UnknownSubscription checks that the API's
+// rejection of an unknown subscription code is reported.
+func TestSubscriptionUserResource_UnknownSubscription(t *testing.T) {
+	testAPI(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pushover" {}
+
+resource "pushover_subscription_user" "ghost" {
+  user_key          = "` + os.Getenv("PUSHOVER_USER_KEY") + `"
+  subscription_code = "no-such-subscription"
+}`,
+				ExpectError: regexp.MustCompile(`Failed to migrate user to subscription`),
+			},
+		},
+	})
+}
+
+// TestSubscriptionUserResource_InvalidSound expects a validation error for a
+// malformed sound name.
+func TestSubscriptionUserResource_InvalidSound(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_subscription_user" "alice" {
+  user_key          = "utest123456789abcdefghijklmnop"
+  subscription_code = "ops-alerts"
+  sound             = "air horn"
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Sound`),
+			},
+		},
+	})
+}
@@ -0,0 +1,149 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &TeamDataSource{}
+
+// NewTeamDataSource creates a new team data source.
+func NewTeamDataSource() datasource.DataSource {
+	return &TeamDataSource{}
+}
+
+// TeamDataSource lists the members of a Pushover for Teams team.
+type TeamDataSource struct {
+	clients *clientSet
+}
+
+// TeamDataSourceModel describes the data source data model.
+type TeamDataSourceModel struct {
+	Application types.String `tfsdk:"application"`
+	// Computed
+	ID      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	Members types.List   `tfsdk:"members"`
+}
+
+// teamMemberModel describes a single entry of the members attribute.
+type teamMemberModel struct {
+	Name    types.String `tfsdk:"name"`
+	Email   types.String `tfsdk:"email"`
+	UserKey types.String `tfsdk:"user_key"`
+}
+
+var teamMemberAttrTypes = map[string]attr.Type{
+	"name":     types.StringType,
+	"email":    types.StringType,
+	"user_key": types.StringType,
+}
+
+func (d *TeamDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_team"
+}
+
+func (d *TeamDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the members of a Pushover for Teams team. " +
+			"The Teams API authenticates with the team's API token, so `application` (or the provider-level `api_token`) must refer to the team token.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The team name (used as resource identifier).",
+				Computed:            true,
+			},
+			"application": schema.StringAttribute{
+				MarkdownDescription: "The name of an entry in the provider's `applications` map holding the team's API token. Defaults to the provider-level `api_token`.",
+				Optional:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the team.",
+				Computed:            true,
+			},
+			"members": schema.ListNestedAttribute{
+				MarkdownDescription: "Every member of the team.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The member's name.",
+							Computed:            true,
+						},
+						"email": schema.StringAttribute{
+							MarkdownDescription: "The member's email address.",
+							Computed:            true,
+						},
+						"user_key": schema.StringAttribute{
+							MarkdownDescription: "The member's Pushover user key.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *TeamDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.clients = pd.clients
+}
+
+func (d *TeamDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data TeamDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	team, diags := teamClient(d.clients, data.Application)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	teamResp, err := team.GetTeam(ctx)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Failed to read team", err, nil)
+		return
+	}
+
+	members := make([]teamMemberModel, 0, len(teamResp.Users))
+	for _, m := range teamResp.Users {
+		members = append(members, teamMemberModel{
+			Name:    types.StringValue(m.Name),
+			Email:   types.StringValue(m.Email),
+			UserKey: types.StringValue(m.User),
+		})
+	}
+	membersTF, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: teamMemberAttrTypes}, members)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(teamResp.Name)
+	data.Name = types.StringValue(teamResp.Name)
+	data.Members = membersTF
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,263 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &TeamMemberResource{}
+
+// NewTeamMemberResource creates a new team member resource.
+func NewTeamMemberResource() resource.Resource {
+	return &TeamMemberResource{}
+}
+
+// TeamMemberResource manages the membership of a user in a Pushover for
+// Teams team.
+type TeamMemberResource struct {
+	clients *clientSet
+}
+
+// TeamMemberResourceModel describes the resource data model.
+type TeamMemberResourceModel struct {
+	Email       types.String `tfsdk:"email"`
+	Name        types.String `tfsdk:"name"`
+	Instant     types.Bool   `tfsdk:"instant"`
+	GroupKey    types.String `tfsdk:"group_key"`
+	Application types.String `tfsdk:"application"`
+
+	// Computed
+	ID      types.String `tfsdk:"id"`
+	UserKey types.String `tfsdk:"user_key"`
+}
+
+// teamMemberAttrs maps client validation errors to the attribute that supplied the value.
+var teamMemberAttrs = map[string]path.Path{
+	"email": path.Root("email"),
+	"group": path.Root("group_key"),
+}
+
+// emailPattern loosely matches an email address; Pushover does the rest.
+var emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+$`)
+
+func (r *TeamMemberResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_team_member"
+}
+
+func (r *TeamMemberResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	requiresReplace := []planmodifier.String{
+		stringplanmodifier.RequiresReplace(),
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Adds a user to a Pushover for Teams team by email address, creating their Pushover account if needed, and removes them from the team when destroyed. " +
+			"The Teams API authenticates with the team's API token, so `application` (or the provider-level `api_token`) must refer to the team token rather than an application token.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The member's email address (used as resource identifier).",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"email": schema.StringAttribute{
+				MarkdownDescription: "The member's email address.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(emailPattern, "must be an email address"),
+				},
+				PlanModifiers: requiresReplace,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The member's name, shown in the team's member list. Pushover cannot rename a member, so changing it adds the member again.",
+				Optional:            true,
+				PlanModifiers:       requiresReplace,
+			},
+			"instant": schema.BoolAttribute{
+				MarkdownDescription: "Create the member's account immediately instead of waiting for them to accept the emailed invitation. " +
+					"Changing it adds the member again. Defaults to `false`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"group_key": schema.StringAttribute{
+				MarkdownDescription: "A delivery group to add the new member to. Changing it adds the member again; the group membership is not managed otherwise, so use `pushover_group_user` for that.",
+				Optional:            true,
+				Validators: []validator.String{
+					groupKeyValidator(),
+				},
+				PlanModifiers: requiresReplace,
+			},
+			"application": schema.StringAttribute{
+				MarkdownDescription: "The name of an entry in the provider's `applications` map holding the team's API token. Defaults to the provider-level `api_token`.",
+				Optional:            true,
+				PlanModifiers:       requiresReplace,
+			},
+			"user_key": schema.StringAttribute{
+				MarkdownDescription: "The member's Pushover user key, once Pushover reports it.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *TeamMemberResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	pd, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.clients = pd.clients
+}
+
+// teamClient returns the client for application as a TeamAPI.
+func teamClient(clients *clientSet, application types.String) (pushover.TeamAPI, diag.Diagnostics) {
+	client, diags := clients.get(application)
+	if diags.HasError() {
+		return nil, diags
+	}
+	team, ok := client.(pushover.TeamAPI)
+	if !ok {
+		diags.AddError("Teams Not Supported", fmt.Sprintf("The configured Pushover client (%T) cannot manage teams.", client))
+	}
+	return team, diags
+}
+
+func (r *TeamMemberResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data TeamMemberResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	team, diags := teamClient(r.clients, data.Application)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := team.AddTeamUser(ctx, &pushover.TeamUserRequest{
+		Email:   data.Email.ValueString(),
+		Name:    data.Name.ValueString(),
+		Instant: data.Instant.ValueBool(),
+		Group:   data.GroupKey.ValueString(),
+	})
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Failed to add team member", err, teamMemberAttrs)
+		return
+	}
+	data.ID = data.Email
+	data.UserKey = types.StringNull()
+
+	// The member now exists, so a failure to look up their user key only
+	// delays it until the next refresh.
+	teamResp, err := team.GetTeam(ctx)
+	if err != nil {
+		resp.Diagnostics.AddWarning("Failed to read team", fmt.Sprintf("The member was added, but their user key could not be read: %s", err))
+	} else if member, ok := findTeamMember(teamResp, data.Email.ValueString()); ok && member.User != "" {
+		data.UserKey = types.StringValue(member.User)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TeamMemberResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data TeamMemberResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	team, diags := teamClient(r.clients, data.Application)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	teamResp, err := team.GetTeam(ctx)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Failed to read team", err, nil)
+		return
+	}
+	member, found := findTeamMember(teamResp, data.Email.ValueString())
+	if !found {
+		// Member has been removed externally – remove from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if member.User != "" {
+		data.UserKey = types.StringValue(member.User)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update is not used; all changes require replacement, since the Teams API
+// cannot change a member once added.
+func (r *TeamMemberResource) Update(_ context.Context, _ resource.UpdateRequest, _ *resource.UpdateResponse) {
+}
+
+func (r *TeamMemberResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data TeamMemberResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	team, diags := teamClient(r.clients, data.Application)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if _, err := team.RemoveTeamUser(ctx, data.Email.ValueString()); err != nil {
+		// The member may already have been removed outside Terraform.
+		if teamResp, getErr := team.GetTeam(ctx); getErr == nil {
+			if _, found := findTeamMember(teamResp, data.Email.ValueString()); !found {
+				return
+			}
+		}
+		addClientError(&resp.Diagnostics, "Failed to remove team member", err, teamMemberAttrs)
+	}
+}
+
+// findTeamMember returns the member of team with the given email address,
+// compared case-insensitively as Pushover does.
+func findTeamMember(team *pushover.TeamResponse, email string) (pushover.TeamMember, bool) {
+	for _, m := range team.Users {
+		if strings.EqualFold(m.Email, email) {
+			return m, true
+		}
+	}
+	return pushover.TeamMember{}, false
+}
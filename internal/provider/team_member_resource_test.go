@@ -0,0 +1,231 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
+)
+
+// testTeamProvider configures the provider with token as the "team" application.
+func testTeamProvider(token string) string {
+	return `
+provider "pushover" {
+  applications = {
+    team = { api_token = "` + token + `" }
+  }
+}
+`
+}
+
+// removeTeamMember removes email from the team outside Terraform.
+func removeTeamMember(t *testing.T, token, email string) {
+	t.Helper()
+	baseURL := os.Getenv("PUSHOVER_BASE_URL")
+	if baseURL == "" {
+		baseURL = pushover.DefaultBaseURL
+	}
+	team := pushover.New(token, pushover.WithBaseURL(baseURL))
+	if _, err := team.RemoveTeamUser(context.Background(), email); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestTeamMemberResource_BasicSchema validates the required and optional fields are accepted.
+func TestTeamMemberResource_BasicSchema(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_team_member" "ada" {
+  email     = "ada@example.com"
+  name      = "Ada Lovelace"
+  instant   = true
+  group_key = "gABCdefghijklmnopqrstuvwxyz123"
+}`,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// TestTeamMemberResource_InvalidEmail expects a validation error for a malformed email address.
+func TestTeamMemberResource_InvalidEmail(t *testing.T) {
+	t.Parallel()
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pushover" { api_token = "fake" }
+
+resource "pushover_team_member" "bad" {
+  email = "not an email"
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must be an email address`),
+			},
+		},
+	})
+}
+
+// TestTeamMemberResource_AddAndList adds a member to the team and one of its
+// groups, lists the team, replaces the member when its name and group
+// change, and removes the member on destroy.
+func TestTeamMemberResource_AddAndList(t *testing.T) {
+	srv := testAPI(t)
+	token := testEnv(t, "PUSHOVER_TEAM_TOKEN")
+	groupKey := os.Getenv("PUSHOVER_GROUP_KEY")
+	memberCount := func(want int) resource.TestCheckFunc {
+		return onFake(srv, func(*terraform.State) error {
+			if _, members, err := srv.Team(pushovertest.TeamToken); err != nil || len(members) != want {
+				return fmt.Errorf("team members = %+v, %v; want %d", members, err, want)
+			}
+			return nil
+		})
+	}
+	config := func(name, group string) string {
+		return testTeamProvider(token) + `
+resource "pushover_team_member" "ada" {
+  email       = "ada@example.com"
+  name        = "` + name + `"
+  instant     = true
+  ` + group + `
+  application = "team"
+}
+
+data "pushover_team" "ops" {
+  application = "team"
+  depends_on  = [pushover_team_member.ada]
+}`
+	}
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             memberCount(0),
+		Steps: []resource.TestStep{
+			{
+				Config: config("Ada", `group_key   = "`+groupKey+`"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pushover_team_member.ada", "id", "ada@example.com"),
+					resource.TestCheckResourceAttrSet("pushover_team_member.ada", "user_key"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.pushover_team.ops", "members.*", map[string]string{"email": "ada@example.com"}),
+					memberCount(1),
+					onFake(srv, resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("data.pushover_team.ops", "name", "Fake Team"),
+						resource.TestCheckResourceAttr("data.pushover_team.ops", "members.#", "1"),
+						resource.TestCheckResourceAttrPair("data.pushover_team.ops", "members.0.user_key", "pushover_team_member.ada", "user_key"),
+						func(s *terraform.State) error {
+							key := s.RootModule().Resources["pushover_team_member.ada"].Primary.Attributes["user_key"]
+							if _, members, _ := srv.Group(pushovertest.GroupKey); len(members) != 1 || members[0].User != key {
+								return fmt.Errorf("expected %s in the group, got %+v", key, members)
+							}
+							if _, members, _ := srv.Team(pushovertest.TeamToken); !members[0].Instant {
+								return fmt.Errorf("expected an instant account, got %+v", members[0])
+							}
+							return nil
+						},
+					)),
+				),
+			},
+			{
+				Config: config("Ada Lovelace", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("pushover_team_member.ada", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pushover_team_member.ada", "name", "Ada Lovelace"),
+					resource.TestCheckNoResourceAttr("pushover_team_member.ada", "group_key"),
+					resource.TestCheckResourceAttrSet("pushover_team_member.ada", "user_key"),
+					memberCount(1),
+					onFake(srv, func(*terraform.State) error {
+						if _, members, _ := srv.Team(pushovertest.TeamToken); members[0].Name != "Ada Lovelace" {
+							return fmt.Errorf("expected the member to be added again under the new name, got %+v", members[0])
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+// TestTeamMemberResource_RemovedExternally checks that a member removed
+// outside Terraform is added again on the next apply.
+func TestTeamMemberResource_RemovedExternally(t *testing.T) {
+	srv := testAPI(t)
+	token := testEnv(t, "PUSHOVER_TEAM_TOKEN")
+	config := testTeamProvider(token) + `
+resource "pushover_team_member" "ada" {
+  email       = "ada@example.com"
+  application = "team"
+}`
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig:          func() { removeTeamMember(t, token, "ada@example.com") },
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: onFake(srv, func(*terraform.State) error {
+					if _, members, _ := srv.Team(pushovertest.TeamToken); len(members) != 1 {
+						return fmt.Errorf("expected the member to be added again, got %+v", members)
+					}
+					return nil
+				}),
+			},
+		},
+	})
+}
+
+// TestTeamMemberResource_DestroyAfterExternalRemoval checks that destroying a
+// member who already left the team succeeds.
+func TestTeamMemberResource_DestroyAfterExternalRemoval(t *testing.T) {
+	testAPI(t)
+	token := testEnv(t, "PUSHOVER_TEAM_TOKEN")
+	config := testTeamProvider(token) + `
+resource "pushover_team_member" "ada" {
+  email       = "ada@example.com"
+  application = "team"
+}`
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				// Leaves the member in state while they are gone, so that the
+				// destroy at the end of the test removes a missing member.
+				PreConfig:          func() { removeTeamMember(t, token, "ada@example.com") },
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
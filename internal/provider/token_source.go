@@ -0,0 +1,147 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// tokenCommandTimeout bounds how long api_token_command may run.
+const tokenCommandTimeout = 30 * time.Second
+
+// tokenSource groups the mutually exclusive ways of supplying an API token.
+// The same attributes appear at the provider level and in every entry of the
+// applications map; base is the path of the object that holds them.
+type tokenSource struct {
+	base    path.Path
+	token   types.String
+	file    types.String
+	command types.List
+}
+
+// configured returns the names of the token attributes that are set.
+func (s tokenSource) configured() []string {
+	var names []string
+	if !s.token.IsNull() {
+		names = append(names, "api_token")
+	}
+	if !s.file.IsNull() {
+		names = append(names, "api_token_file")
+	}
+	if !s.command.IsNull() {
+		names = append(names, "api_token_command")
+	}
+	return names
+}
+
+// validate reports conflicting token attributes and malformed commands.
+func (s tokenSource) validate(ctx context.Context) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if names := s.configured(); len(names) > 1 {
+		diags.AddAttributeError(
+			s.base.AtName(names[1]),
+			"Conflicting API Token Sources",
+			fmt.Sprintf("Only one of api_token, api_token_file or api_token_command may be set, got: %s.", strings.Join(names, ", ")),
+		)
+	}
+
+	if !s.command.IsNull() && !s.command.IsUnknown() {
+		var argv []types.String
+		diags.Append(s.command.ElementsAs(ctx, &argv, false)...)
+		if len(argv) == 0 || (!argv[0].IsUnknown() && argv[0].ValueString() == "") {
+			diags.AddAttributeError(
+				s.base.AtName("api_token_command"),
+				"Invalid API Token Command",
+				"api_token_command must list the executable followed by its arguments, for example [\"pass\", \"show\", \"pushover\"].",
+			)
+		}
+	}
+
+	return diags
+}
+
+// resolve returns the token from whichever source is set, or "" when none is.
+// Diagnostics never include the token itself.
+func (s tokenSource) resolve(ctx context.Context) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	switch {
+	case !s.token.IsNull() && !s.token.IsUnknown():
+		return s.token.ValueString(), diags
+
+	case !s.file.IsNull() && !s.file.IsUnknown():
+		attrPath := s.base.AtName("api_token_file")
+		name := s.file.ValueString()
+		info, err := os.Stat(name)
+		if err != nil {
+			diags.AddAttributeError(attrPath, "Unable to Read API Token File", err.Error())
+			return "", diags
+		}
+		if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+			diags.AddAttributeWarning(
+				attrPath,
+				"API Token File Permissions Too Open",
+				fmt.Sprintf("%s is accessible by other users (mode %04o). Restrict it with chmod 600.", name, info.Mode().Perm()),
+			)
+		}
+		content, err := os.ReadFile(name)
+		if err != nil {
+			diags.AddAttributeError(attrPath, "Unable to Read API Token File", err.Error())
+			return "", diags
+		}
+		token := strings.TrimSpace(string(content))
+		if token == "" {
+			diags.AddAttributeError(attrPath, "Empty API Token File", fmt.Sprintf("%s does not contain a token.", name))
+		}
+		return token, diags
+
+	case !s.command.IsNull() && !s.command.IsUnknown():
+		attrPath := s.base.AtName("api_token_command")
+		var argv []string
+		diags.Append(s.command.ElementsAs(ctx, &argv, false)...)
+		if diags.HasError() {
+			return "", diags
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, tokenCommandTimeout)
+		defer cancel()
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			detail := err.Error()
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				detail = fmt.Sprintf("the command did not finish within %s", tokenCommandTimeout)
+			}
+			// Only stderr is reported; stdout may hold a partial token.
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				detail += "\n\n" + msg
+			}
+			diags.AddAttributeError(attrPath, "API Token Command Failed", fmt.Sprintf("Running %q failed: %s", argv[0], detail))
+			return "", diags
+		}
+		token := strings.TrimSpace(stdout.String())
+		if token == "" {
+			diags.AddAttributeError(attrPath, "Empty API Token", fmt.Sprintf("%q did not print a token on stdout.", argv[0]))
+		}
+		return token, diags
+	}
+
+	return "", diags
+}
@@ -0,0 +1,130 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func commandSource(argv ...string) tokenSource {
+	return tokenSource{
+		base:    path.Empty(),
+		token:   types.StringNull(),
+		file:    types.StringNull(),
+		command: types.ListValueMust(types.StringType, stringValues(argv)),
+	}
+}
+
+func fileSource(name string) tokenSource {
+	return tokenSource{
+		base:    path.Empty(),
+		token:   types.StringNull(),
+		file:    types.StringValue(name),
+		command: types.ListNull(types.StringType),
+	}
+}
+
+func stringValues(in []string) []attr.Value {
+	out := make([]attr.Value, len(in))
+	for i, v := range in {
+		out[i] = types.StringValue(v)
+	}
+	return out
+}
+
+// diagText flattens diagnostics so tests can search them for leaked secrets.
+func diagText(diags diag.Diagnostics) string {
+	var b strings.Builder
+	for _, d := range diags {
+		b.WriteString(d.Summary())
+		b.WriteString(d.Detail())
+	}
+	return b.String()
+}
+
+func TestTokenSource_FileTrimmed(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(name, []byte("  file_token \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	token, diags := fileSource(name).resolve(context.Background())
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if token != "file_token" {
+		t.Errorf("expected trimmed token, got %q", token)
+	}
+}
+
+func TestTokenSource_FilePermissionWarning(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not enforced on Windows")
+	}
+	name := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(name, []byte("secret_file_token"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(name, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, diags := fileSource(name).resolve(context.Background())
+	if diags.WarningsCount() != 1 {
+		t.Fatalf("expected one warning, got %v", diags)
+	}
+	if strings.Contains(diagText(diags), "secret_file_token") {
+		t.Error("diagnostics contain the token")
+	}
+}
+
+func TestTokenSource_Command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	token, diags := commandSource("sh", "-c", "printf 'command_token\\n'").resolve(context.Background())
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if token != "command_token" {
+		t.Errorf("expected command_token, got %q", token)
+	}
+}
+
+func TestTokenSource_CommandFailureDoesNotEchoStdout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	_, diags := commandSource("sh", "-c", "echo secret_command_token; echo helper locked >&2; exit 3").resolve(context.Background())
+	if !diags.HasError() {
+		t.Fatal("expected an error")
+	}
+	text := diagText(diags)
+	if strings.Contains(text, "secret_command_token") {
+		t.Errorf("diagnostics contain the token: %s", text)
+	}
+	if !strings.Contains(text, "helper locked") {
+		t.Errorf("expected stderr in diagnostics, got: %s", text)
+	}
+}
+
+func TestTokenSource_Conflicts(t *testing.T) {
+	src := commandSource("pass", "show", "pushover")
+	src.token = types.StringValue("secret_inline_token")
+	diags := src.validate(context.Background())
+	if !diags.HasError() {
+		t.Fatal("expected a conflict error")
+	}
+	if strings.Contains(diagText(diags), "secret_inline_token") {
+		t.Error("diagnostics contain the token")
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/time/rate"
+)
+
+// validateTransport checks the syntax of the endpoint and transport
+// attributes that are known at validation time.
+func (m PushoverProviderModel) validateTransport() diag.Diagnostics {
+	var diags diag.Diagnostics
+	if isKnown(m.BaseURL) {
+		_, d := parseEndpointURL(path.Root("base_url"), m.BaseURL.ValueString())
+		diags.Append(d...)
+	}
+	if isKnown(m.ProxyURL) {
+		_, d := parseEndpointURL(path.Root("proxy_url"), m.ProxyURL.ValueString())
+		diags.Append(d...)
+	}
+	if isKnown(m.RequestTimeout) {
+		_, d := parseRequestTimeout(m.RequestTimeout.ValueString())
+		diags.Append(d...)
+	}
+	return diags
+}
+
+// transport builds the base URL and HTTP client shared by every Pushover
+// client the provider creates. base_url falls back to PUSHOVER_BASE_URL and
+// then to the public API; proxy_url falls back to the standard proxy
+// environment variables.
+func (m PushoverProviderModel) transport() (string, *http.Client, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	baseURL := pushover.DefaultBaseURL
+	baseAttr := m.BaseURL.ValueString()
+	if baseAttr == "" {
+		baseAttr = os.Getenv("PUSHOVER_BASE_URL")
+	}
+	if baseAttr != "" {
+		u, d := parseEndpointURL(path.Root("base_url"), baseAttr)
+		diags.Append(d...)
+		if u != nil {
+			baseURL = strings.TrimSuffix(u.String(), "/")
+		}
+	}
+
+	timeout := pushover.DefaultTimeout
+	if isKnown(m.RequestTimeout) {
+		t, d := parseRequestTimeout(m.RequestTimeout.ValueString())
+		diags.Append(d...)
+		timeout = t
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if isKnown(m.ProxyURL) {
+		u, d := parseEndpointURL(path.Root("proxy_url"), m.ProxyURL.ValueString())
+		diags.Append(d...)
+		if u != nil {
+			transport.Proxy = http.ProxyURL(u)
+		}
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	caPEM, caAttr := m.CACertPEM.ValueString(), path.Root("ca_cert_pem")
+	if isKnown(m.CACertFile) {
+		caAttr = path.Root("ca_cert_file")
+		content, err := os.ReadFile(m.CACertFile.ValueString())
+		if err != nil {
+			diags.AddAttributeError(caAttr, "Unable to Read CA Certificate File", err.Error())
+		}
+		caPEM = string(content)
+	}
+	if caPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(caPEM)) {
+			diags.AddAttributeError(caAttr, "Invalid CA Certificate", "No PEM-encoded certificates could be parsed.")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if m.InsecureSkipVerify.ValueBool() {
+		tlsConfig.InsecureSkipVerify = true
+		diags.AddAttributeWarning(
+			path.Root("insecure_skip_verify"),
+			"TLS Verification Disabled",
+			"The provider will not verify the certificate of the Pushover API or proxy. Only use this against a local mock server.",
+		)
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return baseURL, &http.Client{Transport: transport, Timeout: timeout}, diags
+}
+
+// defaultRateLimitBurst is the burst used when rate_limit is set without
+// rate_limit_burst.
+const defaultRateLimitBurst = 5
+
+// rateLimiter returns the token bucket shared by every client of the
+// provider, or nil when rate_limit is unset or 0.
+func (m PushoverProviderModel) rateLimiter() *rate.Limiter {
+	rps, burst := 0.0, defaultRateLimitBurst
+	if !m.RateLimit.IsNull() && !m.RateLimit.IsUnknown() {
+		rps = m.RateLimit.ValueFloat64()
+	}
+	if !m.RateLimitBurst.IsNull() && !m.RateLimitBurst.IsUnknown() {
+		burst = int(m.RateLimitBurst.ValueInt64())
+	}
+	if rps == 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(rps), burst)
+}
+
+// parseEndpointURL parses an absolute http or https URL.
+func parseEndpointURL(attrPath path.Path, raw string) (*url.URL, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		diags.AddAttributeError(
+			attrPath,
+			"Invalid URL",
+			fmt.Sprintf("Expected an absolute http or https URL, got %q.", raw),
+		)
+		return nil, diags
+	}
+	return u, diags
+}
+
+// parseRequestTimeout parses a positive Go duration such as "30s".
+func parseRequestTimeout(raw string) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		diags.AddAttributeError(
+			path.Root("request_timeout"),
+			"Invalid Request Timeout",
+			fmt.Sprintf("Expected a positive duration such as \"30s\" or \"1m\", got %q.", raw),
+		)
+		return pushover.DefaultTimeout, diags
+	}
+	return d, diags
+}
+
+// isKnown reports whether v holds a configured value.
+func isKnown(v types.String) bool {
+	return !v.IsNull() && !v.IsUnknown()
+}
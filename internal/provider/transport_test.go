@@ -0,0 +1,167 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func transportModel() PushoverProviderModel {
+	return PushoverProviderModel{
+		BaseURL:            types.StringNull(),
+		RequestTimeout:     types.StringNull(),
+		ProxyURL:           types.StringNull(),
+		CACertPEM:          types.StringNull(),
+		CACertFile:         types.StringNull(),
+		InsecureSkipVerify: types.BoolNull(),
+		RateLimit:          types.Float64Null(),
+		RateLimitBurst:     types.Int64Null(),
+	}
+}
+
+func srvCertPEM(srv *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+}
+
+func TestTransport_Defaults(t *testing.T) {
+	t.Setenv("PUSHOVER_BASE_URL", "")
+	baseURL, client, diags := transportModel().transport()
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if baseURL != pushover.DefaultBaseURL {
+		t.Errorf("baseURL = %q, want %q", baseURL, pushover.DefaultBaseURL)
+	}
+	if client.Timeout != pushover.DefaultTimeout {
+		t.Errorf("timeout = %s, want %s", client.Timeout, pushover.DefaultTimeout)
+	}
+}
+
+func TestTransport_BaseURLFromEnv(t *testing.T) {
+	t.Setenv("PUSHOVER_BASE_URL", "http://127.0.0.1:8080/1/")
+	baseURL, _, diags := transportModel().transport()
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if baseURL != "http://127.0.0.1:8080/1" {
+		t.Errorf("baseURL = %q", baseURL)
+	}
+}
+
+func TestTransport_TimeoutAndProxy(t *testing.T) {
+	m := transportModel()
+	m.RequestTimeout = types.StringValue("5s")
+	m.ProxyURL = types.StringValue("http://proxy.internal:3128")
+	_, client, diags := m.transport()
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if client.Timeout != 5*time.Second {
+		t.Errorf("timeout = %s, want 5s", client.Timeout)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.pushover.net/1/sounds.json", nil)
+	proxy, err := client.Transport.(*http.Transport).Proxy(req)
+	if err != nil || proxy == nil || proxy.Host != "proxy.internal:3128" {
+		t.Errorf("proxy = %v, %v", proxy, err)
+	}
+}
+
+func TestTransport_InvalidValues(t *testing.T) {
+	m := transportModel()
+	m.BaseURL = types.StringValue("api.pushover.net")
+	m.ProxyURL = types.StringValue("socks://proxy")
+	m.RequestTimeout = types.StringValue("-1s")
+	diags := m.validateTransport()
+	if diags.ErrorsCount() != 3 {
+		t.Fatalf("expected 3 errors, got: %v", diags)
+	}
+}
+
+func TestTransport_CACertFile(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":1,"request":"r","sounds":{"magic":"Magic"}}`))
+	}))
+	defer srv.Close()
+
+	// Without the test server's certificate the request must fail verification.
+	m := transportModel()
+	m.BaseURL = types.StringValue(srv.URL)
+	baseURL, httpClient, _ := m.transport()
+	if _, err := pushover.New("tok", pushover.WithBaseURL(baseURL), pushover.WithHTTPClient(httpClient)).GetSounds(t.Context()); err == nil {
+		t.Fatal("expected a certificate verification error")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, srvCertPEM(srv), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m.CACertFile = types.StringValue(caFile)
+	baseURL, httpClient, diags := m.transport()
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	sounds, err := pushover.New("tok", pushover.WithBaseURL(baseURL), pushover.WithHTTPClient(httpClient)).GetSounds(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sounds) != 1 {
+		t.Errorf("expected 1 sound, got %d", len(sounds))
+	}
+}
+
+func TestTransport_InvalidCACert(t *testing.T) {
+	m := transportModel()
+	m.CACertPEM = types.StringValue("not a certificate")
+	_, _, diags := m.transport()
+	if !strings.Contains(diagText(diags), "Invalid CA Certificate") {
+		t.Errorf("expected an invalid CA certificate error, got: %v", diags)
+	}
+}
+
+func TestTransport_InsecureSkipVerifyWarns(t *testing.T) {
+	m := transportModel()
+	m.InsecureSkipVerify = types.BoolValue(true)
+	_, client, diags := m.transport()
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Errorf("expected a single warning, got: %v", diags)
+	}
+	if !client.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify {
+		t.Error("expected InsecureSkipVerify to be set")
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	m := transportModel()
+	if m.rateLimiter() != nil {
+		t.Fatal("expected no limiter without rate_limit")
+	}
+
+	m.RateLimit = types.Float64Value(2.5)
+	limiter := m.rateLimiter()
+	if limiter == nil || limiter.Limit() != 2.5 || limiter.Burst() != defaultRateLimitBurst {
+		t.Fatalf("unexpected limiter: %+v", limiter)
+	}
+
+	m.RateLimitBurst = types.Int64Value(10)
+	limiter = m.rateLimiter()
+	if limiter.Limit() != 2.5 || limiter.Burst() != 10 {
+		t.Errorf("limit = %v, burst = %d", limiter.Limit(), limiter.Burst())
+	}
+
+	m.RateLimit = types.Float64Value(0)
+	if m.rateLimiter() != nil {
+		t.Error("expected rate_limit = 0 to disable the limiter")
+	}
+}
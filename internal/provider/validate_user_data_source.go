@@ -7,9 +7,10 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/Josh-Archer/terraform-provider-pushover/internal/pushover"
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -23,18 +24,19 @@ func NewValidateUserDataSource() datasource.DataSource {
 
 // ValidateUserDataSource validates a Pushover user or group key.
 type ValidateUserDataSource struct {
-	client *pushover.Client
+	clients *clientSet
 }
 
 // ValidateUserDataSourceModel describes the data source data model.
 type ValidateUserDataSourceModel struct {
-	UserKey  types.String `tfsdk:"user_key"`
-	Device   types.String `tfsdk:"device"`
-	APIToken types.String `tfsdk:"api_token"`
+	UserKey     types.String `tfsdk:"user_key"`
+	Device      types.String `tfsdk:"device"`
+	APIToken    types.String `tfsdk:"api_token"`
+	Application types.String `tfsdk:"application"`
 	// Computed
-	IsGroup  types.Bool `tfsdk:"is_group"`
-	Devices  types.List `tfsdk:"devices"`
-	Licenses types.List `tfsdk:"licenses"`
+	IsGroup  types.Bool   `tfsdk:"is_group"`
+	Devices  types.List   `tfsdk:"devices"`
+	Licenses types.List   `tfsdk:"licenses"`
 	ID       types.String `tfsdk:"id"`
 }
 
@@ -54,10 +56,20 @@ func (d *ValidateUserDataSource) Schema(_ context.Context, _ datasource.SchemaRe
 			"user_key": schema.StringAttribute{
 				MarkdownDescription: "The Pushover user or group key to validate.",
 				Required:            true,
+				Validators: []validator.String{
+					userKeyValidator(),
+				},
 			},
 			"device": schema.StringAttribute{
 				MarkdownDescription: "Optionally restrict validation to a specific device name.",
 				Optional:            true,
+				Validators: []validator.String{
+					deviceNameValidator(),
+				},
+			},
+			"application": schema.StringAttribute{
+				MarkdownDescription: "The name of an entry in the provider's `applications` map whose API token is used for this validation. Defaults to the provider-level `api_token`.",
+				Optional:            true,
 			},
 			"api_token": schema.StringAttribute{
 				MarkdownDescription: "Override the provider-level API token for this validation.",
@@ -86,15 +98,15 @@ func (d *ValidateUserDataSource) Configure(_ context.Context, req datasource.Con
 	if req.ProviderData == nil {
 		return
 	}
-	client, ok := req.ProviderData.(*pushover.Client)
+	pd, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *pushover.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
-	d.client = client
+	d.clients = pd.clients
 }
 
 func (d *ValidateUserDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -104,6 +116,12 @@ func (d *ValidateUserDataSource) Read(ctx context.Context, req datasource.ReadRe
 		return
 	}
 
+	client, diags := d.clients.getWithOverride(data.Application, data.APIToken)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	validateReq := &pushover.ValidateRequest{
 		User: data.UserKey.ValueString(),
 	}
@@ -114,7 +132,7 @@ func (d *ValidateUserDataSource) Read(ctx context.Context, req datasource.ReadRe
 		validateReq.Token = data.APIToken.ValueString()
 	}
 
-	result, err := d.client.ValidateUser(ctx, validateReq)
+	result, err := client.ValidateUser(ctx, validateReq)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to validate Pushover user", err.Error())
 		return
@@ -0,0 +1,136 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var (
+	deviceRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,25}$`)
+	soundRegexp  = regexp.MustCompile(`^[A-Za-z0-9_-]{1,20}$`)
+)
+
+// maxMemoLength is the longest memo Pushover stores for a group member.
+const maxMemoLength = 200
+
+// identifierValidator checks a string attribute against the format of a
+// Pushover identifier. The value is never echoed back, since keys are secrets.
+type identifierValidator struct {
+	summary string
+	kind    string
+	rule    string
+	valid   func(string) bool
+	// list accepts a comma-separated list, checking each element.
+	list bool
+}
+
+var _ validator.String = identifierValidator{}
+
+// validKey reports whether s passes the client's own key format check.
+func validKey(s string) bool {
+	return pushover.ValidateKey("key", s) == nil
+}
+
+// keyValidator accepts identifiers in Pushover's key format, reporting
+// failures with summary and naming the expected kind of key.
+func keyValidator(summary, kind string) validator.String {
+	return identifierValidator{summary: summary, kind: kind, rule: "30 letters and digits", valid: validKey}
+}
+
+// userKeyValidator accepts Pushover user or group keys.
+func userKeyValidator() validator.String {
+	return keyValidator("Invalid User Key", "user or group key")
+}
+
+// groupKeyValidator accepts Pushover group keys.
+func groupKeyValidator() validator.String {
+	return keyValidator("Invalid Group Key", "group key")
+}
+
+// receiptValidator accepts emergency message receipts.
+func receiptValidator() validator.String {
+	return keyValidator("Invalid Receipt", "receipt")
+}
+
+// deviceNameValidator accepts device names as registered with Pushover.
+func deviceNameValidator() validator.String {
+	return identifierValidator{summary: "Invalid Device Name", kind: "device name", rule: "up to 25 letters, digits, underscores and hyphens", valid: deviceRegexp.MatchString}
+}
+
+// deviceListValidator accepts a comma-separated list of device names, as the
+// messages API does.
+func deviceListValidator() validator.String {
+	return identifierValidator{summary: "Invalid Device Name", kind: "device name", rule: "up to 25 letters, digits, underscores and hyphens, separated by commas", valid: deviceRegexp.MatchString, list: true}
+}
+
+// soundValidator accepts the names of built-in and custom sounds.
+func soundValidator() validator.String {
+	return identifierValidator{summary: "Invalid Sound", kind: "sound name", rule: "up to 20 letters, digits, underscores and hyphens", valid: soundRegexp.MatchString}
+}
+
+func (v identifierValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be a Pushover %s: %s", v.kind, v.rule)
+}
+
+func (v identifierValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v identifierValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	values := []string{req.ConfigValue.ValueString()}
+	if v.list {
+		values = strings.Split(values[0], ",")
+	}
+	for _, value := range values {
+		if !v.valid(value) {
+			resp.Diagnostics.AddAttributeError(
+				req.Path,
+				v.summary,
+				fmt.Sprintf("Attribute %s must be a Pushover %s: %s.", req.Path, v.kind, v.rule),
+			)
+			return
+		}
+	}
+}
+
+// memoValidator limits memos to the length Pushover stores.
+func memoValidator() validator.String {
+	return memoLengthValidator{}
+}
+
+type memoLengthValidator struct{}
+
+var _ validator.String = memoLengthValidator{}
+
+func (v memoLengthValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("memo must be at most %d characters", maxMemoLength)
+}
+
+func (v memoLengthValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v memoLengthValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if n := utf8.RuneCountInString(req.ConfigValue.ValueString()); n > maxMemoLength {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Memo Too Long",
+			fmt.Sprintf("Attribute %s must be at most %d characters, got %d.", req.Path, maxMemoLength, n),
+		)
+	}
+}
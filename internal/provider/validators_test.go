@@ -0,0 +1,61 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidators(t *testing.T) {
+	cases := []struct {
+		name      string
+		validator validator.String
+		value     types.String
+		wantError bool
+	}{
+		{"user key", userKeyValidator(), types.StringValue("uQiRzpo4DXghDmr9QzzfQu27cmVRsG"), false},
+		{"user key too short", userKeyValidator(), types.StringValue("uABC"), true},
+		{"user key list", userKeyValidator(), types.StringValue("uQiRzpo4DXghDmr9QzzfQu27cmVRsG,uQiRzpo4DXghDmr9QzzfQu27cmVRsG"), true},
+		{"user key unknown", userKeyValidator(), types.StringUnknown(), false},
+		{"group key", groupKeyValidator(), types.StringValue("gznej3rKEVAvPUxu9vvNnqpmZpokzF"), false},
+		{"group key path", groupKeyValidator(), types.StringValue("gznej3rKEVAvPUxu9vvNnqpmZpo/.."), true},
+		{"receipt", receiptValidator(), types.StringValue("rLqVuqTRh62UzxtmqiaLzQmVcPgiCy"), false},
+		{"receipt too long", receiptValidator(), types.StringValue("rLqVuqTRh62UzxtmqiaLzQmVcPgiCy1"), true},
+		{"receipt symbols", receiptValidator(), types.StringValue("rLqVuqTRh62UzxtmqiaLzQmVcPgi-y"), true},
+		{"device", deviceNameValidator(), types.StringValue("pixel_8-pro"), false},
+		{"device space", deviceNameValidator(), types.StringValue("my phone"), true},
+		{"device too long", deviceNameValidator(), types.StringValue(strings.Repeat("d", 26)), true},
+		{"device null", deviceNameValidator(), types.StringNull(), false},
+		{"device list as name", deviceNameValidator(), types.StringValue("pixel,ipad"), true},
+		{"device list", deviceListValidator(), types.StringValue("pixel_8-pro,ipad"), false},
+		{"device list single", deviceListValidator(), types.StringValue("pixel"), false},
+		{"device list bad element", deviceListValidator(), types.StringValue("pixel,my phone"), true},
+		{"device list empty element", deviceListValidator(), types.StringValue("pixel,"), true},
+		{"sound", soundValidator(), types.StringValue("bugle"), false},
+		{"custom sound", soundValidator(), types.StringValue("my_alarm-2"), false},
+		{"sound space", soundValidator(), types.StringValue("air horn"), true},
+		{"sound too long", soundValidator(), types.StringValue(strings.Repeat("s", 21)), true},
+		{"memo", memoValidator(), types.StringValue(strings.Repeat("é", 200)), false},
+		{"memo too long", memoValidator(), types.StringValue(strings.Repeat("m", 201)), true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := validator.StringRequest{Path: path.Root("attr"), ConfigValue: tc.value}
+			resp := &validator.StringResponse{}
+			tc.validator.ValidateString(context.Background(), req, resp)
+			if resp.Diagnostics.HasError() != tc.wantError {
+				t.Fatalf("wantError=%t, got: %v", tc.wantError, resp.Diagnostics)
+			}
+			if tc.wantError && strings.Contains(diagText(resp.Diagnostics), tc.value.ValueString()) {
+				t.Errorf("diagnostic echoes the value: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover
+
+import "context"
+
+// API is the set of Pushover operations implemented by *Client. Depend on it
+// rather than on *Client to substitute a fake in tests.
+//
+// Methods are only ever added to API in a new major version, so existing
+// implementations keep compiling.
+type API interface {
+	SendMessage(ctx context.Context, req *MessageRequest) (*MessageResponse, error)
+	SendMessageToUsers(ctx context.Context, req *MessageRequest, users []string) (*MultiMessageResponse, error)
+	GetReceipt(ctx context.Context, receipt string) (*ReceiptResponse, error)
+	CancelReceipt(ctx context.Context, receipt string) (*APIResponse, error)
+	GetSounds(ctx context.Context) ([]Sound, error)
+	ValidateUser(ctx context.Context, req *ValidateRequest) (*ValidateResponse, error)
+	GetGroup(ctx context.Context, groupKey string) (*GroupResponse, error)
+	RenameGroup(ctx context.Context, groupKey, name string) (*APIResponse, error)
+	AddGroupUser(ctx context.Context, groupKey, user, device, memo string) (*APIResponse, error)
+	RemoveGroupUser(ctx context.Context, groupKey, user, device string) (*APIResponse, error)
+	EnableGroupUser(ctx context.Context, groupKey, user, device string) (*APIResponse, error)
+	DisableGroupUser(ctx context.Context, groupKey, user, device string) (*APIResponse, error)
+}
+
+// GlanceAPI is implemented by clients that can update glances. It is kept
+// apart from API so that adding it did not break existing implementations.
+type GlanceAPI interface {
+	UpdateGlance(ctx context.Context, req *GlanceRequest) (*APIResponse, error)
+}
+
+// SubscriptionAPI is implemented by clients that can migrate users to
+// subscriptions.
+type SubscriptionAPI interface {
+	MigrateSubscription(ctx context.Context, req *SubscriptionMigrateRequest) (*SubscriptionMigrateResponse, error)
+}
+
+// TeamAPI is implemented by clients that can manage Pushover for Teams
+// members. Its methods need a client created with the team's API token.
+type TeamAPI interface {
+	GetTeam(ctx context.Context) (*TeamResponse, error)
+	AddTeamUser(ctx context.Context, req *TeamUserRequest) (*APIResponse, error)
+	RemoveTeamUser(ctx context.Context, email string) (*APIResponse, error)
+}
+
+// LicenseAPI is implemented by clients that can assign Pushover licenses
+// from the application owner's prepaid credits.
+type LicenseAPI interface {
+	AssignLicense(ctx context.Context, req *LicenseAssignRequest) (*APIResponse, error)
+	GetLicenseCredits(ctx context.Context) (*LicenseCreditsResponse, error)
+}
+
+var (
+	_ API             = (*Client)(nil)
+	_ GlanceAPI       = (*Client)(nil)
+	_ SubscriptionAPI = (*Client)(nil)
+	_ TeamAPI         = (*Client)(nil)
+	_ LicenseAPI      = (*Client)(nil)
+)
@@ -0,0 +1,286 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+// Package callback receives the requests Pushover sends to the callback URL
+// of an emergency-priority message when it is acknowledged.
+//
+// A Handler parses each callback into an Event and delivers it either to a
+// function registered with OnEvent or on the channel returned by Events:
+//
+//	h := callback.New(callback.WithAllowedSources(netip.MustParsePrefix("203.0.113.0/24")))
+//	http.Handle("/pushover/callback", h)
+//	for ev := range h.Events() {
+//		log.Printf("%s acknowledged by %s", ev.Receipt, ev.AcknowledgedBy)
+//	}
+//
+// Pushover repeats a callback that does not receive a 2xx response, so the
+// handler answers with an error status whenever an event could not be
+// delivered, and ignores callbacks for receipts it has already delivered.
+// A callback that arrives while an earlier one for the same receipt is still
+// being delivered is answered with 409 Conflict, so that Pushover repeats it
+// if that delivery fails.
+package callback
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+)
+
+// Defaults used by New.
+const (
+	DefaultBufferSize = 64
+	DefaultDedupTTL   = 24 * time.Hour
+)
+
+// Event is an acknowledgement reported by Pushover.
+type Event struct {
+	Receipt              string    `json:"receipt"`
+	Acknowledged         bool      `json:"acknowledged"`
+	AcknowledgedAt       time.Time `json:"acknowledged_at"`
+	AcknowledgedBy       string    `json:"acknowledged_by"`
+	AcknowledgedByDevice string    `json:"acknowledged_by_device"`
+	// Source is the address the callback was received from.
+	Source netip.Addr `json:"source"`
+}
+
+// Handler is an http.Handler for Pushover callbacks. Create it with New.
+type Handler struct {
+	events          chan Event
+	onEvent         func(context.Context, Event) error
+	allowed         []netip.Prefix
+	forwardedHeader string
+	dedupTTL        time.Duration
+	now             func() time.Time
+
+	mu       sync.Mutex
+	seen     map[string]time.Time
+	inFlight map[string]bool
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// OnEvent delivers events by calling fn instead of sending them on the
+// Events channel. When fn returns an error the callback is answered with 500
+// Internal Server Error, so that Pushover sends it again.
+func OnEvent(fn func(context.Context, Event) error) Option {
+	return func(h *Handler) {
+		h.onEvent = fn
+	}
+}
+
+// WithBufferSize sets the capacity of the Events channel. When the channel
+// is full, callbacks wait for room until Pushover gives up on the request.
+func WithBufferSize(n int) Option {
+	return func(h *Handler) {
+		h.events = make(chan Event, n)
+	}
+}
+
+// WithAllowedSources only accepts callbacks from addresses in prefixes; all
+// others are answered with 403 Forbidden. By default every source is
+// accepted.
+func WithAllowedSources(prefixes ...netip.Prefix) Option {
+	return func(h *Handler) {
+		h.allowed = append(h.allowed, prefixes...)
+	}
+}
+
+// WithForwardedHeader takes the source address from the last entry of the
+// named header, such as "X-Forwarded-For", for handlers behind a reverse
+// proxy. Only use it when the proxy sets the header.
+func WithForwardedHeader(name string) Option {
+	return func(h *Handler) {
+		h.forwardedHeader = name
+	}
+}
+
+// WithDedupTTL sets how long a delivered receipt is remembered, so that
+// redelivered callbacks for it are ignored.
+func WithDedupTTL(d time.Duration) Option {
+	return func(h *Handler) {
+		h.dedupTTL = d
+	}
+}
+
+// New returns a Handler configured by opts.
+func New(opts ...Option) *Handler {
+	h := &Handler{
+		events:   make(chan Event, DefaultBufferSize),
+		dedupTTL: DefaultDedupTTL,
+		now:      time.Now,
+		seen:     map[string]time.Time{},
+		inFlight: map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Events returns the channel on which events are delivered when no OnEvent
+// function is registered.
+func (h *Handler) Events() <-chan Event {
+	return h.events
+}
+
+// ServeHTTP handles one callback request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	source, err := h.source(r)
+	if err != nil || !h.isAllowed(source) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	ev, err := parse(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ev.Source = source
+
+	switch h.claim(ev.Receipt) {
+	case claimDelivered:
+		// Already delivered; acknowledge the redelivery.
+		w.WriteHeader(http.StatusOK)
+		return
+	case claimInFlight:
+		// The first delivery may still fail, so ask Pushover to try again.
+		http.Error(w, "event delivery in progress", http.StatusConflict)
+		return
+	}
+	err = h.deliver(r.Context(), ev)
+	h.finish(ev.Receipt, err == nil)
+	if err != nil {
+		status := http.StatusServiceUnavailable
+		if h.onEvent != nil {
+			status = http.StatusInternalServerError
+		}
+		http.Error(w, "event not delivered", status)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// deliver passes ev to the registered function or the events channel.
+func (h *Handler) deliver(ctx context.Context, ev Event) error {
+	if h.onEvent != nil {
+		return h.onEvent(ctx, ev)
+	}
+	select {
+	case h.events <- ev:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Results of claim.
+const (
+	claimed        = iota // the caller delivers the event
+	claimDelivered        // the receipt was already delivered
+	claimInFlight         // another request is delivering the receipt
+)
+
+// claim marks receipt as being delivered, unless it already was or is.
+func (h *Handler) claim(receipt string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.now()
+	for r, at := range h.seen {
+		if now.Sub(at) > h.dedupTTL {
+			delete(h.seen, r)
+		}
+	}
+	if _, ok := h.seen[receipt]; ok {
+		return claimDelivered
+	}
+	if h.inFlight[receipt] {
+		return claimInFlight
+	}
+	h.inFlight[receipt] = true
+	return claimed
+}
+
+// finish ends the delivery of receipt. A delivered receipt is remembered so
+// that redeliveries are ignored; a failed one is forgotten, so a retry is
+// accepted.
+func (h *Handler) finish(receipt string, delivered bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.inFlight, receipt)
+	if delivered {
+		h.seen[receipt] = h.now()
+	}
+}
+
+// source returns the address the request came from.
+func (h *Handler) source(r *http.Request) (netip.Addr, error) {
+	raw := r.RemoteAddr
+	if h.forwardedHeader != "" {
+		if v := r.Header.Get(h.forwardedHeader); v != "" {
+			parts := strings.Split(v, ",")
+			addr, err := netip.ParseAddr(strings.TrimSpace(parts[len(parts)-1]))
+			return addr.Unmap(), err
+		}
+	}
+	if host, _, err := net.SplitHostPort(raw); err == nil {
+		raw = host
+	}
+	addr, err := netip.ParseAddr(raw)
+	return addr.Unmap(), err
+}
+
+func (h *Handler) isAllowed(addr netip.Addr) bool {
+	if len(h.allowed) == 0 {
+		return true
+	}
+	for _, p := range h.allowed {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parse reads the callback form sent by Pushover.
+func parse(r *http.Request) (Event, error) {
+	if err := r.ParseForm(); err != nil {
+		return Event{}, errors.New("invalid form")
+	}
+	ev := Event{
+		Receipt:              r.PostForm.Get("receipt"),
+		Acknowledged:         r.PostForm.Get("acknowledged") == "1",
+		AcknowledgedBy:       r.PostForm.Get("acknowledged_by"),
+		AcknowledgedByDevice: r.PostForm.Get("acknowledged_by_device"),
+	}
+	if err := pushover.ValidateKey("receipt", ev.Receipt); err != nil {
+		return Event{}, err
+	}
+	if ev.AcknowledgedBy != "" {
+		if err := pushover.ValidateKey("acknowledged_by", ev.AcknowledgedBy); err != nil {
+			return Event{}, err
+		}
+	}
+	if v := r.PostForm.Get("acknowledged_at"); v != "" {
+		sec, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return Event{}, errors.New("invalid acknowledged_at")
+		}
+		ev.AcknowledgedAt = time.Unix(sec, 0)
+	}
+	return ev, nil
+}
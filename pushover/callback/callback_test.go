@@ -0,0 +1,260 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package callback
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testReceipt = "rTestReceipt00000000000000001x"
+	testUser    = "uTestUser000000000000000000001"
+)
+
+func callbackForm() url.Values {
+	return url.Values{
+		"receipt":                {testReceipt},
+		"acknowledged":           {"1"},
+		"acknowledged_at":        {"1700000000"},
+		"acknowledged_by":        {testUser},
+		"acknowledged_by_device": {"iphone"},
+	}
+}
+
+func post(h http.Handler, remote string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.RemoteAddr = remote
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_DeliversOnChannel(t *testing.T) {
+	h := New()
+	if rec := post(h, "198.51.100.7:443", callbackForm()); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %q", rec.Code, rec.Body)
+	}
+
+	select {
+	case ev := <-h.Events():
+		want := Event{
+			Receipt:              testReceipt,
+			Acknowledged:         true,
+			AcknowledgedAt:       time.Unix(1700000000, 0),
+			AcknowledgedBy:       testUser,
+			AcknowledgedByDevice: "iphone",
+			Source:               netip.MustParseAddr("198.51.100.7"),
+		}
+		if !ev.AcknowledgedAt.Equal(want.AcknowledgedAt) {
+			t.Errorf("AcknowledgedAt = %v, want %v", ev.AcknowledgedAt, want.AcknowledgedAt)
+		}
+		ev.AcknowledgedAt = want.AcknowledgedAt
+		if ev != want {
+			t.Errorf("event = %+v, want %+v", ev, want)
+		}
+	default:
+		t.Fatal("no event delivered")
+	}
+}
+
+func TestHandler_OnEvent(t *testing.T) {
+	var got []Event
+	h := New(OnEvent(func(_ context.Context, ev Event) error {
+		got = append(got, ev)
+		return nil
+	}))
+	post(h, "198.51.100.7:443", callbackForm())
+	if len(got) != 1 || got[0].Receipt != testReceipt {
+		t.Fatalf("events = %+v", got)
+	}
+	if len(h.Events()) != 0 {
+		t.Error("event was also sent on the channel")
+	}
+}
+
+func TestHandler_IgnoresRedelivery(t *testing.T) {
+	calls := 0
+	h := New(OnEvent(func(context.Context, Event) error {
+		calls++
+		return nil
+	}))
+	for i := 0; i < 3; i++ {
+		if rec := post(h, "198.51.100.7:443", callbackForm()); rec.Code != http.StatusOK {
+			t.Fatalf("delivery %d: status = %d", i, rec.Code)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
+
+func TestHandler_RedeliversAfterTTL(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	calls := 0
+	h := New(WithDedupTTL(time.Hour), OnEvent(func(context.Context, Event) error {
+		calls++
+		return nil
+	}))
+	h.now = func() time.Time { return now }
+
+	post(h, "198.51.100.7:443", callbackForm())
+	now = now.Add(2 * time.Hour)
+	post(h, "198.51.100.7:443", callbackForm())
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+}
+
+func TestHandler_FailedDeliveryIsRetried(t *testing.T) {
+	fail := true
+	calls := 0
+	h := New(OnEvent(func(context.Context, Event) error {
+		calls++
+		if fail {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	}))
+	if rec := post(h, "198.51.100.7:443", callbackForm()); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	fail = false
+	if rec := post(h, "198.51.100.7:443", callbackForm()); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+}
+
+func TestHandler_RedeliveryDuringDelivery(t *testing.T) {
+	entered := make(chan struct{})
+	result := make(chan error)
+	calls := 0
+	h := New(OnEvent(func(context.Context, Event) error {
+		calls++
+		if calls > 1 {
+			return nil
+		}
+		close(entered)
+		return <-result
+	}))
+
+	first := make(chan int)
+	go func() { first <- post(h, "198.51.100.7:443", callbackForm()).Code }()
+	<-entered
+	if rec := post(h, "198.51.100.7:443", callbackForm()); rec.Code != http.StatusConflict {
+		t.Fatalf("redelivery during delivery: status = %d, want 409", rec.Code)
+	}
+
+	// The first delivery fails, so the next redelivery is delivered.
+	result <- errors.New("downstream unavailable")
+	if code := <-first; code != http.StatusInternalServerError {
+		t.Fatalf("first delivery: status = %d, want 500", code)
+	}
+	for i := 0; i < 2; i++ {
+		if rec := post(h, "198.51.100.7:443", callbackForm()); rec.Code != http.StatusOK {
+			t.Fatalf("redelivery %d: status = %d, want 200", i, rec.Code)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+}
+
+func TestHandler_FullChannel(t *testing.T) {
+	h := New(WithBufferSize(0))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(callbackForm().Encode())).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", rec.Code)
+	}
+}
+
+func TestHandler_Allowlist(t *testing.T) {
+	h := New(WithAllowedSources(netip.MustParsePrefix("203.0.113.0/24")))
+	if rec := post(h, "198.51.100.7:443", callbackForm()); rec.Code != http.StatusForbidden {
+		t.Errorf("outside allowlist: status = %d, want 403", rec.Code)
+	}
+	if rec := post(h, "203.0.113.9:443", callbackForm()); rec.Code != http.StatusOK {
+		t.Errorf("inside allowlist: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestHandler_ForwardedHeader(t *testing.T) {
+	h := New(
+		WithAllowedSources(netip.MustParsePrefix("203.0.113.0/24")),
+		WithForwardedHeader("X-Forwarded-For"),
+	)
+	req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(callbackForm().Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Forwarded-For", "198.51.100.7, 203.0.113.9")
+	req.RemoteAddr = "127.0.0.1:5000"
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if ev := <-h.Events(); ev.Source != netip.MustParseAddr("203.0.113.9") {
+		t.Errorf("source = %v", ev.Source)
+	}
+}
+
+func TestHandler_ForwardedHeaderMappedAddress(t *testing.T) {
+	h := New(
+		WithAllowedSources(netip.MustParsePrefix("203.0.113.0/24")),
+		WithForwardedHeader("X-Forwarded-For"),
+	)
+	req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(callbackForm().Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Forwarded-For", "::ffff:203.0.113.9")
+	req.RemoteAddr = "127.0.0.1:5000"
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if ev := <-h.Events(); ev.Source != netip.MustParseAddr("203.0.113.9") {
+		t.Errorf("source = %v", ev.Source)
+	}
+}
+
+func TestHandler_RejectsInvalidRequests(t *testing.T) {
+	h := New()
+
+	req := httptest.NewRequest(http.MethodGet, "/callback", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status = %d, want 405", rec.Code)
+	}
+
+	for field, value := range map[string]string{
+		"receipt":         "short",
+		"acknowledged_by": "not-a-key",
+		"acknowledged_at": "yesterday",
+	} {
+		form := callbackForm()
+		form.Set(field, value)
+		if rec := post(h, "198.51.100.7:443", form); rec.Code != http.StatusBadRequest {
+			t.Errorf("invalid %s: status = %d, want 400", field, rec.Code)
+		}
+	}
+	if len(h.Events()) != 0 {
+		t.Error("invalid callback delivered an event")
+	}
+}
@@ -0,0 +1,645 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+// Package pushover provides a client for the Pushover REST API.
+//
+// Create a client with New and configure it with options:
+//
+//	client := pushover.New(token,
+//		pushover.WithRetry(pushover.DefaultRetryPolicy),
+//		pushover.WithRateLimit(5, 5),
+//		pushover.WithUserAgent("my-service/1.0"),
+//	)
+//	resp, err := client.SendMessage(ctx, &pushover.MessageRequest{User: userKey, Message: "Deployed"})
+//
+// Code that only needs to call the API should depend on the API interface,
+// which *Client implements, so it can be tested with a fake.
+package pushover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultBaseURL is the Pushover API endpoint used unless WithBaseURL is given.
+const DefaultBaseURL = "https://api.pushover.net/1"
+
+// DefaultTimeout bounds every request unless WithHTTPClient is given.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultUserAgent is sent with every request unless WithUserAgent is given.
+const DefaultUserAgent = "terraform-provider-pushover-go"
+
+// Client is the Pushover API client.
+type Client struct {
+	token      string
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	logger     Logger
+	tracer     trace.Tracer
+	middleware []Middleware
+	limiter    Limiter
+	retry      *RetryPolicy
+}
+
+// Option configures optional behaviour of a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends requests through hc instead of a client with
+// DefaultTimeout. hc itself is not modified.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.httpClient = hc
+		}
+	}
+}
+
+// WithBaseURL targets a custom API endpoint, such as a proxy or a mock server,
+// instead of DefaultBaseURL.
+func WithBaseURL(base string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(base, "/")
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// New creates a Pushover API client for the application token.
+func New(token string, opts ...Option) *Client {
+	c := &Client{
+		token:      token,
+		baseURL:    DefaultBaseURL,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		userAgent:  DefaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.logger != nil || c.tracer != nil || c.limiter != nil || c.retry != nil || len(c.middleware) > 0 {
+		// Copy the client so the caller's transport is left untouched.
+		hc := *c.httpClient
+		hc.Transport = c.transport(hc.Transport)
+		c.httpClient = &hc
+	}
+	return c
+}
+
+// APIResponse is the base Pushover API response.
+type APIResponse struct {
+	Status  int      `json:"status"`
+	Request string   `json:"request"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// APIError is returned when the Pushover API responds with a non-success status.
+// InvalidFields lists the request parameters the API flagged with
+// "<field>": "invalid", such as "user" or "device".
+type APIError struct {
+	HTTPStatus    int
+	Errors        []string
+	InvalidFields []string
+}
+
+// FieldInvalid reports whether the API flagged the request parameter field as
+// invalid.
+func (e *APIError) FieldInvalid(field string) bool {
+	return slices.Contains(e.InvalidFields, field)
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("pushover API error: %s", strings.Join(e.Errors, "; "))
+}
+
+// keyFormat matches Pushover user, group and receipt keys.
+var keyFormat = regexp.MustCompile(`^[A-Za-z0-9]{30}$`)
+
+// ValidationError is returned, before any request is sent, when an argument
+// does not have the format the Pushover API expects. Field is the name of the
+// API parameter, such as "user", "group" or "receipt".
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+// ValidateKey checks that key has the format of a Pushover user, group or
+// receipt key: 30 letters and digits. The key itself is never included in
+// the returned *ValidationError.
+func ValidateKey(field, key string) error {
+	if !keyFormat.MatchString(key) {
+		return &ValidationError{Field: field, Reason: fmt.Sprintf("must be 30 letters and digits, got %d characters", len(key))}
+	}
+	return nil
+}
+
+// MessageRequest holds all fields for sending a Pushover message.
+type MessageRequest struct {
+	Token     string `json:"token"`
+	User      string `json:"user"`
+	Message   string `json:"message"`
+	Title     string `json:"title,omitempty"`
+	URL       string `json:"url,omitempty"`
+	URLTitle  string `json:"url_title,omitempty"`
+	Priority  int    `json:"priority"`
+	Sound     string `json:"sound,omitempty"`
+	Device    string `json:"device,omitempty"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+	HTML      int    `json:"html,omitempty"`
+	Monospace int    `json:"monospace,omitempty"`
+	TTL       int    `json:"ttl,omitempty"`
+	// Emergency priority (priority=2) fields
+	Retry    int    `json:"retry,omitempty"`
+	Expire   int    `json:"expire,omitempty"`
+	Callback string `json:"callback,omitempty"`
+}
+
+// MessageResponse is the response from sending a message.
+type MessageResponse struct {
+	APIResponse
+	Receipt string `json:"receipt,omitempty"`
+}
+
+// MaxRecipientsPerMessage is the number of user keys Pushover accepts in a single message.
+const MaxRecipientsPerMessage = 50
+
+// MessageChunk is the outcome of sending a message to one batch of recipients.
+type MessageChunk struct {
+	Users   []string
+	Request string
+	Receipt string
+	Err     error
+}
+
+// MultiMessageResponse is the response from sending a message to several recipients.
+type MultiMessageResponse struct {
+	Chunks []MessageChunk
+}
+
+// PartialSendError reports the recipients of a multi-recipient message that
+// were not notified because their chunk was rejected. FailedUsers holds the
+// keys themselves; Error masks them like every other client error.
+type PartialSendError struct {
+	FailedUsers []string
+	Total       int
+	Errors      []error
+}
+
+func (e *PartialSendError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	users := make([]string, len(e.FailedUsers))
+	for i, u := range e.FailedUsers {
+		users[i] = mask(u)
+	}
+	return fmt.Sprintf("failed to notify %d of %d recipients (%s): %s",
+		len(e.FailedUsers), e.Total, strings.Join(users, ", "), strings.Join(msgs, "; "))
+}
+
+func (e *PartialSendError) Unwrap() []error {
+	return e.Errors
+}
+
+// ReceiptResponse is the response from polling an emergency receipt.
+type ReceiptResponse struct {
+	APIResponse
+	Acknowledged         int    `json:"acknowledged"`
+	AcknowledgedAt       int64  `json:"acknowledged_at"`
+	AcknowledgedBy       string `json:"acknowledged_by"`
+	AcknowledgedByDevice string `json:"acknowledged_by_device"`
+	LastDeliveredAt      int64  `json:"last_delivered_at"`
+	Expired              int    `json:"expired"`
+	ExpiresAt            int64  `json:"expires_at"`
+	CalledBack           int    `json:"called_back"`
+	CalledBackAt         int64  `json:"called_back_at"`
+}
+
+// SoundsResponse is the response from listing sounds.
+type SoundsResponse struct {
+	APIResponse
+	Sounds map[string]string `json:"sounds"`
+}
+
+// Sound represents a single Pushover sound.
+type Sound struct {
+	Key  string
+	Name string
+}
+
+// ValidateRequest holds fields for validating a user/group key.
+type ValidateRequest struct {
+	Token  string `json:"token"`
+	User   string `json:"user"`
+	Device string `json:"device,omitempty"`
+}
+
+// ValidateResponse is the response from validating a user.
+type ValidateResponse struct {
+	APIResponse
+	Group    int      `json:"group"`
+	Devices  []string `json:"devices"`
+	Licenses []string `json:"licenses"`
+}
+
+// GroupResponse is the response from group API calls.
+type GroupResponse struct {
+	APIResponse
+	Name  string        `json:"name"`
+	Users []GroupMember `json:"users"`
+}
+
+// GroupMember represents a member in a Pushover group.
+type GroupMember struct {
+	User     string `json:"user"`
+	Device   string `json:"device,omitempty"`
+	Memo     string `json:"memo,omitempty"`
+	Disabled bool   `json:"disabled"`
+}
+
+// SendMessage sends a notification via the Pushover API.
+func (c *Client) SendMessage(ctx context.Context, req *MessageRequest) (*MessageResponse, error) {
+	if req.Token == "" {
+		req.Token = c.token
+	}
+
+	params := url.Values{}
+	params.Set("token", req.Token)
+	params.Set("user", req.User)
+	params.Set("message", req.Message)
+	if req.Title != "" {
+		params.Set("title", req.Title)
+	}
+	if req.URL != "" {
+		params.Set("url", req.URL)
+	}
+	if req.URLTitle != "" {
+		params.Set("url_title", req.URLTitle)
+	}
+	params.Set("priority", strconv.Itoa(req.Priority))
+	if req.Sound != "" {
+		params.Set("sound", req.Sound)
+	}
+	if req.Device != "" {
+		params.Set("device", req.Device)
+	}
+	if req.Timestamp != 0 {
+		params.Set("timestamp", strconv.FormatInt(req.Timestamp, 10))
+	}
+	if req.HTML != 0 {
+		params.Set("html", strconv.Itoa(req.HTML))
+	}
+	if req.Monospace != 0 {
+		params.Set("monospace", strconv.Itoa(req.Monospace))
+	}
+	if req.TTL != 0 {
+		params.Set("ttl", strconv.Itoa(req.TTL))
+	}
+	if req.Priority == 2 {
+		params.Set("retry", strconv.Itoa(req.Retry))
+		params.Set("expire", strconv.Itoa(req.Expire))
+		if req.Callback != "" {
+			params.Set("callback", req.Callback)
+		}
+	}
+
+	var resp MessageResponse
+	if err := c.doPost(ctx, "/messages.json", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SendMessageToUsers sends the same notification to several user or group keys.
+// Keys are de-duplicated and sent in chunks of MaxRecipientsPerMessage, using as
+// few API calls as possible. The User field of req is ignored. When some chunks
+// fail, the returned response still describes every chunk and the error is a
+// *PartialSendError listing the keys that were not notified.
+func (c *Client) SendMessageToUsers(ctx context.Context, req *MessageRequest, users []string) (*MultiMessageResponse, error) {
+	keys := make([]string, 0, len(users))
+	seen := make(map[string]bool, len(users))
+	for _, u := range users {
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		keys = append(keys, u)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no user keys given")
+	}
+	sort.Strings(keys)
+
+	resp := &MultiMessageResponse{}
+	partial := &PartialSendError{}
+	for start := 0; start < len(keys); start += MaxRecipientsPerMessage {
+		end := min(start+MaxRecipientsPerMessage, len(keys))
+		chunk := MessageChunk{Users: keys[start:end]}
+
+		chunkReq := *req
+		chunkReq.User = strings.Join(chunk.Users, ",")
+		result, err := c.SendMessage(ctx, &chunkReq)
+		if err != nil {
+			chunk.Err = err
+			partial.FailedUsers = append(partial.FailedUsers, chunk.Users...)
+			partial.Errors = append(partial.Errors, err)
+		} else {
+			chunk.Request = result.Request
+			chunk.Receipt = result.Receipt
+		}
+		resp.Chunks = append(resp.Chunks, chunk)
+	}
+
+	if len(partial.FailedUsers) > 0 {
+		partial.Total = len(keys)
+		return resp, partial
+	}
+	return resp, nil
+}
+
+// GetReceipt retrieves delivery status for an emergency message receipt.
+func (c *Client) GetReceipt(ctx context.Context, receipt string) (*ReceiptResponse, error) {
+	if err := ValidateKey("receipt", receipt); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/receipts/%s.json?token=%s", url.PathEscape(receipt), url.QueryEscape(c.token))
+	var resp ReceiptResponse
+	if err := c.doGet(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CancelReceipt cancels an outstanding emergency notification.
+func (c *Client) CancelReceipt(ctx context.Context, receipt string) (*APIResponse, error) {
+	if err := ValidateKey("receipt", receipt); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("token", c.token)
+	var resp APIResponse
+	if err := c.doPost(ctx, fmt.Sprintf("/receipts/%s/cancel.json", url.PathEscape(receipt)), params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetSounds returns the list of available Pushover sounds.
+func (c *Client) GetSounds(ctx context.Context) ([]Sound, error) {
+	path := fmt.Sprintf("/sounds.json?token=%s", url.QueryEscape(c.token))
+	var resp SoundsResponse
+	if err := c.doGet(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	sounds := make([]Sound, 0, len(resp.Sounds))
+	for k, v := range resp.Sounds {
+		sounds = append(sounds, Sound{Key: k, Name: v})
+	}
+	return sounds, nil
+}
+
+// ValidateUser validates a Pushover user or group key.
+func (c *Client) ValidateUser(ctx context.Context, req *ValidateRequest) (*ValidateResponse, error) {
+	if req.Token == "" {
+		req.Token = c.token
+	}
+	params := url.Values{}
+	params.Set("token", req.Token)
+	params.Set("user", req.User)
+	if req.Device != "" {
+		params.Set("device", req.Device)
+	}
+	var resp ValidateResponse
+	if err := c.doPost(ctx, "/users/validate.json", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetGroup retrieves information about a Pushover delivery group.
+func (c *Client) GetGroup(ctx context.Context, groupKey string) (*GroupResponse, error) {
+	if err := ValidateKey("group", groupKey); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/groups/%s.json?token=%s", url.PathEscape(groupKey), url.QueryEscape(c.token))
+	var resp GroupResponse
+	if err := c.doGet(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RenameGroup renames a Pushover delivery group.
+func (c *Client) RenameGroup(ctx context.Context, groupKey, name string) (*APIResponse, error) {
+	if err := ValidateKey("group", groupKey); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("token", c.token)
+	params.Set("name", name)
+	var resp APIResponse
+	if err := c.doPost(ctx, fmt.Sprintf("/groups/%s/rename.json", url.PathEscape(groupKey)), params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddGroupUser adds a user to a Pushover delivery group.
+func (c *Client) AddGroupUser(ctx context.Context, groupKey, user, device, memo string) (*APIResponse, error) {
+	if err := validateGroupUser(groupKey, user); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("token", c.token)
+	params.Set("user", user)
+	if device != "" {
+		params.Set("device", device)
+	}
+	if memo != "" {
+		params.Set("memo", memo)
+	}
+	var resp APIResponse
+	if err := c.doPost(ctx, fmt.Sprintf("/groups/%s/add_user.json", url.PathEscape(groupKey)), params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RemoveGroupUser removes a user from a Pushover delivery group.
+func (c *Client) RemoveGroupUser(ctx context.Context, groupKey, user, device string) (*APIResponse, error) {
+	if err := validateGroupUser(groupKey, user); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("token", c.token)
+	params.Set("user", user)
+	if device != "" {
+		params.Set("device", device)
+	}
+	var resp APIResponse
+	if err := c.doPost(ctx, fmt.Sprintf("/groups/%s/delete_user.json", url.PathEscape(groupKey)), params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// EnableGroupUser re-enables a disabled user in a Pushover delivery group.
+func (c *Client) EnableGroupUser(ctx context.Context, groupKey, user, device string) (*APIResponse, error) {
+	if err := validateGroupUser(groupKey, user); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("token", c.token)
+	params.Set("user", user)
+	if device != "" {
+		params.Set("device", device)
+	}
+	var resp APIResponse
+	if err := c.doPost(ctx, fmt.Sprintf("/groups/%s/enable_user.json", url.PathEscape(groupKey)), params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DisableGroupUser disables a user in a Pushover delivery group.
+func (c *Client) DisableGroupUser(ctx context.Context, groupKey, user, device string) (*APIResponse, error) {
+	if err := validateGroupUser(groupKey, user); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("token", c.token)
+	params.Set("user", user)
+	if device != "" {
+		params.Set("device", device)
+	}
+	var resp APIResponse
+	if err := c.doPost(ctx, fmt.Sprintf("/groups/%s/disable_user.json", url.PathEscape(groupKey)), params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// validateGroupUser checks the keys passed to the group membership calls.
+func validateGroupUser(groupKey, user string) error {
+	if err := ValidateKey("group", groupKey); err != nil {
+		return err
+	}
+	return ValidateKey("user", user)
+}
+
+func (c *Client) doPost(ctx context.Context, path string, params url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, strings.NewReader(params.Encode()))
+	if err != nil {
+		return c.redactError(fmt.Errorf("creating request: %w", redactURLError(err)), params.Get("token"), params.Get("user"))
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", c.userAgent)
+	return c.redactError(c.do(req, endpointName(path), out), params.Get("token"), params.Get("user"))
+}
+
+func (c *Client) doGet(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return c.redactError(fmt.Errorf("creating request: %w", redactURLError(err)))
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	return c.redactError(c.do(req, endpointName(path), out))
+}
+
+// do sends req and decodes the JSON response into out. endpoint is the API
+// path with keys masked, used for tracing. Errors may contain secrets and
+// must be passed through redactError by the caller.
+func (c *Client) do(req *http.Request, endpoint string, out interface{}) (err error) {
+	ctx, span := c.startSpan(req.Context(), req.Method, endpoint)
+	req = req.WithContext(ctx)
+	var status int
+	var reqID string
+	defer func() { c.endSpan(ctx, span, status, reqID, err) }()
+
+	if c.limiter != nil {
+		// Queue for the first attempt here, where only the caller's context
+		// applies, rather than inside the HTTP client's timeout.
+		if err := c.limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("waiting for rate limit: %w", err)
+		}
+		held := &atomic.Bool{}
+		held.Store(true)
+		req = req.WithContext(context.WithValue(ctx, limiterTokenKey{}, held))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", redactURLError(err))
+	}
+	defer resp.Body.Close()
+	status = resp.StatusCode
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	reqID = requestID(body)
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	// Check for API-level errors
+	type statusChecker struct {
+		Status int      `json:"status"`
+		Errors []string `json:"errors"`
+	}
+	var sc statusChecker
+	_ = json.Unmarshal(body, &sc)
+	if sc.Status != 1 {
+		return &APIError{HTTPStatus: resp.StatusCode, Errors: sc.Errors, InvalidFields: invalidFields(body)}
+	}
+
+	return nil
+}
+
+// invalidFields returns, sorted, the names of the fields an error response
+// flags as "invalid".
+func invalidFields(body []byte) []string {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) != nil {
+		return nil
+	}
+	var invalid []string
+	for name, raw := range fields {
+		var flag string
+		if json.Unmarshal(raw, &flag) == nil && flag == "invalid" {
+			invalid = append(invalid, name)
+		}
+	}
+	sort.Strings(invalid)
+	return invalid
+}
+
+// IsGroupKey returns true if the validation response indicates the key is a group key.
+func (v *ValidateResponse) IsGroupKey() bool {
+	return v.Group == 1
+}
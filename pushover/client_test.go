@@ -0,0 +1,1131 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+)
+
+// Well-formed keys for calls that validate their arguments.
+const (
+	testUserKey  = "uQiRzpo4DXghDmr9QzzfQu27cmVRsG"
+	testGroupKey = "gznej3rKEVAvPUxu9vvNnqpmZpokzF"
+	testReceipt  = "rLqVuqTRh62UzxtmqiaLzQmVcPSiCy"
+)
+
+// ----- SendMessage -----
+
+func TestSendMessage_Success(t *testing.T) {
+	body := fixtureBody(t, "send_message", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		if r.FormValue("token") != "test_token" {
+			t.Errorf("unexpected token: %s", r.FormValue("token"))
+		}
+		if r.FormValue("user") != "test_user" {
+			t.Errorf("unexpected user: %s", r.FormValue("user"))
+		}
+		if r.FormValue("message") != "hello world" {
+			t.Errorf("unexpected message: %s", r.FormValue("message"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("test_token", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.SendMessage(context.Background(), &pushover.MessageRequest{
+		User:    "test_user",
+		Message: "hello world",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := fixtureResponse[pushover.MessageResponse](t, "send_message", 0).Request; resp.Request != want {
+		t.Errorf("expected request id %q, got %s", want, resp.Request)
+	}
+}
+
+func TestSendMessage_WithAllFields(t *testing.T) {
+	body := fixtureBody(t, "send_message", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		// Verify all optional fields are forwarded.
+		checks := map[string]string{
+			"title":     "My Title",
+			"url":       "https://example.com",
+			"url_title": "Click Here",
+			"priority":  "1",
+			"sound":     "pushover",
+			"device":    "iphone",
+			"html":      "1",
+		}
+		for field, want := range checks {
+			if got := r.FormValue(field); got != want {
+				t.Errorf("field %q: want %q, got %q", field, want, got)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	_, err := client.SendMessage(context.Background(), &pushover.MessageRequest{
+		User:     "u",
+		Message:  "test",
+		Title:    "My Title",
+		URL:      "https://example.com",
+		URLTitle: "Click Here",
+		Priority: 1,
+		Sound:    "pushover",
+		Device:   "iphone",
+		HTML:     1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSendMessage_EmergencyFieldsForwarded(t *testing.T) {
+	body := fixtureBody(t, "emergency_receipt", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		if r.FormValue("priority") != "2" {
+			t.Errorf("expected priority 2, got %s", r.FormValue("priority"))
+		}
+		if r.FormValue("retry") != "60" {
+			t.Errorf("expected retry 60, got %s", r.FormValue("retry"))
+		}
+		if r.FormValue("expire") != "3600" {
+			t.Errorf("expected expire 3600, got %s", r.FormValue("expire"))
+		}
+		if r.FormValue("callback") != "https://example.com/cb" {
+			t.Errorf("unexpected callback: %s", r.FormValue("callback"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.SendMessage(context.Background(), &pushover.MessageRequest{
+		User:     "u",
+		Message:  "emergency",
+		Priority: 2,
+		Retry:    60,
+		Expire:   3600,
+		Callback: "https://example.com/cb",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := fixtureResponse[pushover.MessageResponse](t, "emergency_receipt", 0).Receipt; resp.Receipt != want {
+		t.Errorf("expected receipt %s, got %s", want, resp.Receipt)
+	}
+}
+
+func TestSendMessage_APIError(t *testing.T) {
+	body := fixtureBody(t, "send_invalid_user", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("bad_token", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	_, err := client.SendMessage(context.Background(), &pushover.MessageRequest{
+		User:    "bad_user",
+		Message: "test",
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestSendMessage_TokenOverride(t *testing.T) {
+	body := fixtureBody(t, "send_message", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		if r.FormValue("token") != "override_token" {
+			t.Errorf("expected override_token, got %s", r.FormValue("token"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("provider_token", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	_, err := client.SendMessage(context.Background(), &pushover.MessageRequest{
+		Token:   "override_token",
+		User:    "u",
+		Message: "test",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSendMessageToUsers_Chunks(t *testing.T) {
+	var calls []int
+	body := fixtureBody(t, "send_message", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		calls = append(calls, len(strings.Split(r.FormValue("user"), ",")))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	users := make([]string, 0, 121)
+	for i := 0; i < 120; i++ {
+		users = append(users, fmt.Sprintf("u%03d", i))
+	}
+	users = append(users, "u000") // duplicates are sent only once
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.SendMessageToUsers(context.Background(), &pushover.MessageRequest{Message: "fan out"}, users)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calls) != 3 || calls[0] != 50 || calls[1] != 50 || calls[2] != 20 {
+		t.Errorf("expected chunks of 50/50/20, got %v", calls)
+	}
+	if len(resp.Chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(resp.Chunks))
+	}
+	for i, chunk := range resp.Chunks {
+		if chunk.Request == "" {
+			t.Errorf("chunk %d has no request id", i)
+		}
+	}
+}
+
+func TestSendMessageToUsers_PartialFailure(t *testing.T) {
+	failure := fixtureBody(t, "send_invalid_user", 0)
+	body := fixtureBody(t, "send_message", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.FormValue("user"), "u050") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write(failure)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	users := make([]string, 0, 60)
+	for i := 0; i < 60; i++ {
+		users = append(users, fmt.Sprintf("u%03d", i))
+	}
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.SendMessageToUsers(context.Background(), &pushover.MessageRequest{Message: "fan out"}, users)
+	var partial *pushover.PartialSendError
+	if !errors.As(err, &partial) {
+		t.Fatalf("expected *pushover.PartialSendError, got %v", err)
+	}
+	if len(partial.FailedUsers) != 10 || partial.FailedUsers[0] != "u050" {
+		t.Errorf("unexpected failed users: %v", partial.FailedUsers)
+	}
+	if msg := err.Error(); strings.Contains(msg, "u050") || !strings.Contains(msg, pushover.Redacted) {
+		t.Errorf("failed user keys not masked: %s", msg)
+	}
+	if resp == nil || resp.Chunks[0].Err != nil || resp.Chunks[1].Err == nil {
+		t.Errorf("expected first chunk to succeed and second to fail")
+	}
+}
+
+// ----- GetSounds -----
+
+func TestGetSounds_Success(t *testing.T) {
+	body := fixtureBody(t, "sounds_custom", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	sounds, err := client.GetSounds(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := len(fixtureResponse[pushover.SoundsResponse](t, "sounds_custom", 0).Sounds); len(sounds) != want {
+		t.Errorf("expected %d sounds, got %d", want, len(sounds))
+	}
+
+	// Verify all keys are present.
+	soundMap := make(map[string]string)
+	for _, s := range sounds {
+		soundMap[s.Key] = s.Name
+	}
+	if soundMap["pushover"] != "Pushover (default)" {
+		t.Errorf("unexpected sound name for 'pushover': %s", soundMap["pushover"])
+	}
+	if soundMap["bike"] != "Bike" {
+		t.Errorf("unexpected sound name for 'bike': %s", soundMap["bike"])
+	}
+}
+
+func TestGetSounds_APIError(t *testing.T) {
+	body := fixtureBody(t, "invalid_token", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("bad", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	_, err := client.GetSounds(context.Background())
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+// ----- ValidateUser -----
+
+func TestValidateUser_RegularUser(t *testing.T) {
+	body := fixtureBody(t, "validate_user_licenses", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		if r.FormValue("user") != "valid_user_key" {
+			t.Errorf("unexpected user: %s", r.FormValue("user"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.ValidateUser(context.Background(), &pushover.ValidateRequest{
+		User: "valid_user_key",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.IsGroupKey() {
+		t.Error("expected non-group key")
+	}
+	if len(resp.Devices) != 2 {
+		t.Errorf("expected 2 devices, got %d", len(resp.Devices))
+	}
+	if resp.Devices[0] != "iphone" && resp.Devices[1] != "iphone" {
+		t.Error("expected 'iphone' in devices")
+	}
+}
+
+func TestValidateUser_GroupKey(t *testing.T) {
+	body := fixtureBody(t, "group_membership", 6)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.ValidateUser(context.Background(), &pushover.ValidateRequest{
+		User: "group_key",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.IsGroupKey() {
+		t.Error("expected group key")
+	}
+}
+
+func TestValidateUser_InvalidKey(t *testing.T) {
+	body := fixtureBody(t, "validate_user_licenses", 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	_, err := client.ValidateUser(context.Background(), &pushover.ValidateRequest{
+		User: "invalid",
+	})
+	if err == nil {
+		t.Fatal("expected error for invalid user key")
+	}
+	var apiErr *pushover.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *pushover.APIError, got %T", err)
+	}
+	if apiErr.HTTPStatus != http.StatusBadRequest {
+		t.Errorf("expected HTTP status 400, got %d", apiErr.HTTPStatus)
+	}
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0] != "user key is invalid" || !apiErr.FieldInvalid("user") {
+		t.Errorf("unexpected errors: %v", apiErr.Errors)
+	}
+}
+
+func TestValidateUser_WithDeviceFilter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		if r.FormValue("device") != "iphone" {
+			t.Errorf("expected device 'iphone', got %s", r.FormValue("device"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":1,"request":"r1","group":0,"devices":["iphone"],"licenses":["iOS"]}`))
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.ValidateUser(context.Background(), &pushover.ValidateRequest{
+		User:   "valid_user",
+		Device: "iphone",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Devices) != 1 || resp.Devices[0] != "iphone" {
+		t.Errorf("unexpected devices: %v", resp.Devices)
+	}
+}
+
+// ----- GetReceipt -----
+
+func TestGetReceipt_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":1,"request":"r1","acknowledged":1,"acknowledged_at":1700000000,"acknowledged_by":"uABC","acknowledged_by_device":"iphone","last_delivered_at":1700000010,"expired":0,"expires_at":1700003600,"called_back":0,"called_back_at":0}`))
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.GetReceipt(context.Background(), testReceipt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Acknowledged != 1 {
+		t.Errorf("expected acknowledged=1, got %d", resp.Acknowledged)
+	}
+	if resp.AcknowledgedBy != "uABC" {
+		t.Errorf("unexpected acknowledged_by: %s", resp.AcknowledgedBy)
+	}
+}
+
+func TestGetReceipt_Expired(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":1,"request":"r1","acknowledged":0,"acknowledged_at":0,"acknowledged_by":"","acknowledged_by_device":"","last_delivered_at":1700000010,"expired":1,"expires_at":1700003600,"called_back":0,"called_back_at":0}`))
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.GetReceipt(context.Background(), testReceipt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Expired != 1 {
+		t.Errorf("expected expired=1, got %d", resp.Expired)
+	}
+}
+
+// ----- CancelReceipt -----
+
+func TestCancelReceipt_Success(t *testing.T) {
+	body := fixtureBody(t, "emergency_receipt", 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.CancelReceipt(context.Background(), testReceipt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Status != 1 {
+		t.Errorf("expected status 1, got %d", resp.Status)
+	}
+}
+
+// ----- Group -----
+
+func TestGetGroup_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":1,"request":"r1","name":"My Group","users":[{"user":"u1","device":"iphone","memo":"lead","disabled":false},{"user":"u2","device":"","memo":"","disabled":true}]}`))
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.GetGroup(context.Background(), testGroupKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Name != "My Group" {
+		t.Errorf("expected 'My Group', got %s", resp.Name)
+	}
+	if len(resp.Users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(resp.Users))
+	}
+	if resp.Users[0].User != "u1" {
+		t.Errorf("expected u1, got %s", resp.Users[0].User)
+	}
+	if !resp.Users[1].Disabled {
+		t.Error("expected u2 to be disabled")
+	}
+}
+
+func TestAddGroupUser_Success(t *testing.T) {
+	body := fixtureBody(t, "group_membership", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		if r.FormValue("user") != testUserKey {
+			t.Errorf("unexpected user: %s", r.FormValue("user"))
+		}
+		if r.FormValue("memo") != "test memo" {
+			t.Errorf("unexpected memo: %s", r.FormValue("memo"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	_, err := client.AddGroupUser(context.Background(), testGroupKey, testUserKey, "", "test memo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRemoveGroupUser_Success(t *testing.T) {
+	body := fixtureBody(t, "group_membership", 5)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	_, err := client.RemoveGroupUser(context.Background(), testGroupKey, testUserKey, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEnableDisableGroupUser(t *testing.T) {
+	body := fixtureBody(t, "group_membership", 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+
+	if _, err := client.DisableGroupUser(context.Background(), testGroupKey, testUserKey, ""); err != nil {
+		t.Fatalf("DisableGroupUser: %v", err)
+	}
+	if _, err := client.EnableGroupUser(context.Background(), testGroupKey, testUserKey, ""); err != nil {
+		t.Fatalf("EnableGroupUser: %v", err)
+	}
+}
+
+func TestRenameGroup_Success(t *testing.T) {
+	body := fixtureBody(t, "group_membership", 3)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		if r.FormValue("name") != "New Name" {
+			t.Errorf("expected name 'New Name', got %s", r.FormValue("name"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	_, err := client.RenameGroup(context.Background(), testGroupKey, "New Name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// ----- Redaction -----
+
+const secretToken = "azGDORePK8gMaC0QOYAMyEEuzJnyUi"
+
+// ----- Glances -----
+
+func TestUpdateGlance_Success(t *testing.T) {
+	body := fixtureBody(t, "glance_update", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/glances.json" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		for field, want := range map[string]string{"user": testUserKey, "device": "watch", "title": "Deploys", "count": "0", "percent": "99"} {
+			if got := r.PostForm.Get(field); got != want {
+				t.Errorf("%s = %q, want %q", field, got, want)
+			}
+		}
+		if r.PostForm.Has("text") || r.PostForm.Has("subtext") {
+			t.Errorf("unset fields were sent: %v", r.PostForm)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	title, count, percent := "Deploys", 0, 99
+	_, err := client.UpdateGlance(context.Background(), &pushover.GlanceRequest{
+		User: testUserKey, Device: "watch", Title: &title, Count: &count, Percent: &percent,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateGlance_Validation(t *testing.T) {
+	long := strings.Repeat("x", pushover.MaxGlanceTextLength+1)
+	tooHigh := 101
+	cases := map[string]pushover.GlanceRequest{
+		"user":    {User: "bad", Text: &long},
+		"glance":  {User: testUserKey},
+		"subtext": {User: testUserKey, Subtext: &long},
+		"percent": {User: testUserKey, Percent: &tooHigh},
+	}
+	client := pushover.New("tok", pushover.WithBaseURL("http://127.0.0.1:0"))
+	for field, req := range cases {
+		_, err := client.UpdateGlance(context.Background(), &req)
+		var vErr *pushover.ValidationError
+		if !errors.As(err, &vErr) || vErr.Field != field {
+			t.Errorf("%s: err = %v, want a ValidationError for %s", field, err, field)
+		}
+	}
+}
+
+// ----- Subscriptions -----
+
+func TestMigrateSubscription_Success(t *testing.T) {
+	body := fixtureBody(t, "subscription_migrate", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/subscriptions/migrate.json" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		for field, want := range map[string]string{"subscription": "abc-sub", "user": testUserKey, "device_name": "phone", "sound": "bugle"} {
+			if got := r.PostForm.Get(field); got != want {
+				t.Errorf("%s = %q, want %q", field, got, want)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.MigrateSubscription(context.Background(), &pushover.SubscriptionMigrateRequest{
+		Subscription: "abc-sub", User: testUserKey, DeviceName: "phone", Sound: "bugle",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := fixtureResponse[pushover.SubscriptionMigrateResponse](t, "subscription_migrate", 0).SubscribedUserKey; resp.SubscribedUserKey != want {
+		t.Errorf("SubscribedUserKey = %q", resp.SubscribedUserKey)
+	}
+}
+
+func TestMigrateSubscription_Validation(t *testing.T) {
+	client := pushover.New("tok", pushover.WithBaseURL("http://127.0.0.1:0"))
+	for field, req := range map[string]pushover.SubscriptionMigrateRequest{
+		"subscription": {User: testUserKey},
+		"user":         {Subscription: "abc-sub", User: "bad"},
+	} {
+		_, err := client.MigrateSubscription(context.Background(), &req)
+		var vErr *pushover.ValidationError
+		if !errors.As(err, &vErr) || vErr.Field != field {
+			t.Errorf("%s: err = %v, want a ValidationError for %s", field, err, field)
+		}
+	}
+}
+
+// ----- Teams -----
+
+func TestGetTeam_Success(t *testing.T) {
+	body := fixtureBody(t, "team", 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/teams.json" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("token"); got != "team-tok" {
+			t.Errorf("token = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("team-tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.GetTeam(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := fixtureResponse[pushover.TeamResponse](t, "team", 1)
+	if resp.Name != want.Name || !slices.Equal(resp.Users, want.Users) || len(resp.Users) == 0 {
+		t.Errorf("unexpected team: %+v", resp)
+	}
+}
+
+func TestAddTeamUser_Success(t *testing.T) {
+	body := fixtureBody(t, "team", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/teams/add_user.json" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		for field, want := range map[string]string{"token": "team-tok", "email": "ada@example.com", "name": "Ada", "instant": "1", "group": testGroupKey} {
+			if got := r.PostForm.Get(field); got != want {
+				t.Errorf("%s = %q, want %q", field, got, want)
+			}
+		}
+		if r.PostForm.Has("password") {
+			t.Error("password should not be sent when empty")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("team-tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	_, err := client.AddTeamUser(context.Background(), &pushover.TeamUserRequest{
+		Email: "ada@example.com", Name: "Ada", Instant: true, Group: testGroupKey,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTeamUser_Validation(t *testing.T) {
+	client := pushover.New("tok", pushover.WithBaseURL("http://127.0.0.1:0"))
+	ctx := context.Background()
+	checks := map[string]func() error{
+		"email": func() error {
+			_, err := client.AddTeamUser(ctx, &pushover.TeamUserRequest{Email: "ada"})
+			return err
+		},
+		"group": func() error {
+			_, err := client.AddTeamUser(ctx, &pushover.TeamUserRequest{Email: "ada@example.com", Group: "bad"})
+			return err
+		},
+	}
+	for field, call := range checks {
+		var vErr *pushover.ValidationError
+		if err := call(); !errors.As(err, &vErr) || vErr.Field != field {
+			t.Errorf("%s: err = %v, want a ValidationError for %s", field, err, field)
+		}
+	}
+	var vErr *pushover.ValidationError
+	if _, err := client.RemoveTeamUser(ctx, "not an email"); !errors.As(err, &vErr) || vErr.Field != "email" {
+		t.Errorf("RemoveTeamUser: err = %v, want a ValidationError for email", err)
+	}
+}
+
+// ----- Licenses -----
+
+func TestAssignLicense_Success(t *testing.T) {
+	body := fixtureBody(t, "licenses", 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/licenses/assign.json" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		for field, want := range map[string]string{"token": "tok", "email": "ada@example.com", "os": "Desktop"} {
+			if got := r.PostForm.Get(field); got != want {
+				t.Errorf("%s = %q, want %q", field, got, want)
+			}
+		}
+		if r.PostForm.Has("user") {
+			t.Error("user should not be sent when assigning by email")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	_, err := client.AssignLicense(context.Background(), &pushover.LicenseAssignRequest{Email: "ada@example.com", OS: pushover.LicenseDesktop})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAssignLicense_Validation(t *testing.T) {
+	client := pushover.New("tok", pushover.WithBaseURL("http://127.0.0.1:0"))
+	cases := map[string]struct {
+		req   pushover.LicenseAssignRequest
+		field string
+	}{
+		"no recipient":   {pushover.LicenseAssignRequest{}, "user"},
+		"both":           {pushover.LicenseAssignRequest{User: testUserKey, Email: "ada@example.com"}, "user"},
+		"bad user":       {pushover.LicenseAssignRequest{User: "bad"}, "user"},
+		"bad email":      {pushover.LicenseAssignRequest{Email: "ada"}, "email"},
+		"unknown system": {pushover.LicenseAssignRequest{User: testUserKey, OS: "BeOS"}, "os"},
+	}
+	for name, tc := range cases {
+		_, err := client.AssignLicense(context.Background(), &tc.req)
+		var vErr *pushover.ValidationError
+		if !errors.As(err, &vErr) || vErr.Field != tc.field {
+			t.Errorf("%s: err = %v, want a ValidationError for %s", name, err, tc.field)
+		}
+	}
+}
+
+func TestGetLicenseCredits_Success(t *testing.T) {
+	body := fixtureBody(t, "licenses", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/licenses.json" || r.URL.Query().Get("token") != "tok" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	resp, err := client.GetLicenseCredits(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := fixtureResponse[pushover.LicenseCreditsResponse](t, "licenses", 0).Credits; resp.Credits != want {
+		t.Errorf("Credits = %d, want %d", resp.Credits, want)
+	}
+}
+
+func TestErrors_NetworkErrorRedactsToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	base := srv.URL
+	srv.Close()
+
+	client := pushover.New(secretToken, pushover.WithBaseURL(base), pushover.WithHTTPClient(&http.Client{}))
+	calls := map[string]func() error{
+		"GetSounds": func() error {
+			_, err := client.GetSounds(context.Background())
+			return err
+		},
+		"GetReceipt": func() error {
+			_, err := client.GetReceipt(context.Background(), testReceipt)
+			return err
+		},
+		"GetGroup": func() error {
+			_, err := client.GetGroup(context.Background(), testGroupKey)
+			return err
+		},
+		"SendMessage": func() error {
+			_, err := client.SendMessage(context.Background(), &pushover.MessageRequest{User: testUserKey, Message: "hi"})
+			return err
+		},
+	}
+	for name, call := range calls {
+		err := call()
+		if err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+		for _, secret := range []string{secretToken, testReceipt, testGroupKey, testUserKey} {
+			if strings.Contains(err.Error(), secret) {
+				t.Errorf("%s: error leaks a secret: %v", name, err)
+			}
+		}
+		// GET requests carry the token in the query string; it must be masked, not dropped.
+		if strings.HasPrefix(name, "Get") && !strings.Contains(err.Error(), "token="+pushover.Redacted) {
+			t.Errorf("%s: expected a redacted URL, got: %v", name, err)
+		}
+	}
+}
+
+func TestErrors_APIErrorRedactsToken(t *testing.T) {
+	// Make the recorded error echo the token back, as some API errors do.
+	body := bytes.Replace(fixtureBody(t, "invalid_token", 0), []byte("application token is invalid"), []byte("application token "+secretToken+" is invalid"), 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New(secretToken, pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	_, err := client.GetSounds(context.Background())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if strings.Contains(err.Error(), secretToken) {
+		t.Errorf("error leaks the token: %v", err)
+	}
+	var apiErr *pushover.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != http.StatusBadRequest {
+		t.Fatalf("expected a wrapped *APIError, got %T", err)
+	}
+	if strings.Contains(apiErr.Error(), secretToken) {
+		t.Errorf("unwrapped *APIError leaks the token: %v", apiErr)
+	}
+
+	// Network errors carry the request URL, with the token in its query.
+	srv.Close()
+	_, err = client.GetSounds(context.Background())
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Fatalf("expected a wrapped *url.Error, got %v", err)
+	}
+	if strings.Contains(urlErr.URL, secretToken) || strings.Contains(urlErr.Error(), secretToken) {
+		t.Errorf("unwrapped *url.Error leaks the token: %v", urlErr)
+	}
+}
+
+func TestRequests_StringMasksSecrets(t *testing.T) {
+	msg := pushover.MessageRequest{Token: secretToken, User: testUserKey, Message: "hi"}
+	val := pushover.ValidateRequest{Token: secretToken, User: testUserKey}
+	for _, out := range []string{
+		msg.String(), fmt.Sprint(&msg), msg.LogValue().String(),
+		val.String(), fmt.Sprintf("%v", val), val.LogValue().String(),
+	} {
+		if strings.Contains(out, secretToken) || strings.Contains(out, testUserKey) {
+			t.Errorf("output leaks a secret: %s", out)
+		}
+	}
+}
+
+// ----- Path parameter validation -----
+
+func TestKeys_InvalidRejectedBeforeRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("no request should be sent for an invalid key")
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	cases := map[string]struct {
+		field string
+		call  func() error
+	}{
+		"GetReceipt traversal": {"receipt", func() error {
+			_, err := client.GetReceipt(context.Background(), "../groups/"+testGroupKey)
+			return err
+		}},
+		"CancelReceipt empty": {"receipt", func() error {
+			_, err := client.CancelReceipt(context.Background(), "")
+			return err
+		}},
+		"GetGroup query": {"group", func() error {
+			_, err := client.GetGroup(context.Background(), "gznej3rKEVAvPUxu9vvNnqpmZpok?x=")
+			return err
+		}},
+		"RenameGroup short": {"group", func() error {
+			_, err := client.RenameGroup(context.Background(), "gkey", "name")
+			return err
+		}},
+		"AddGroupUser bad user": {"user", func() error {
+			_, err := client.AddGroupUser(context.Background(), testGroupKey, "u1", "", "")
+			return err
+		}},
+		"RemoveGroupUser bad group": {"group", func() error {
+			_, err := client.RemoveGroupUser(context.Background(), testGroupKey+"/", testUserKey, "")
+			return err
+		}},
+	}
+	for name, tc := range cases {
+		err := tc.call()
+		var vErr *pushover.ValidationError
+		if !errors.As(err, &vErr) {
+			t.Errorf("%s: expected *ValidationError, got %v", name, err)
+			continue
+		}
+		if vErr.Field != tc.field {
+			t.Errorf("%s: expected field %q, got %q", name, tc.field, vErr.Field)
+		}
+	}
+}
+
+func TestKeys_PathEscaped(t *testing.T) {
+	body := fixtureBody(t, "group_membership", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/groups/"+testGroupKey+"/add_user.json" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	if _, err := client.AddGroupUser(context.Background(), testGroupKey, testUserKey, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// ----- Logging -----
+
+type logEntry struct {
+	level  string
+	msg    string
+	fields map[string]interface{}
+}
+
+type recordingLogger struct {
+	entries []logEntry
+}
+
+func (l *recordingLogger) Debug(_ context.Context, msg string, fields map[string]interface{}) {
+	l.entries = append(l.entries, logEntry{"DEBUG", msg, fields})
+}
+
+func (l *recordingLogger) Trace(_ context.Context, msg string, fields map[string]interface{}) {
+	l.entries = append(l.entries, logEntry{"TRACE", msg, fields})
+}
+
+func TestLogger_DebugSummaryAndRedactedTrace(t *testing.T) {
+	body := fixtureBody(t, "validate_user_licenses", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Limit-App-Limit", "10000")
+		w.Header().Set("X-Limit-App-Remaining", "9999")
+		w.Header().Set("X-Limit-App-Reset", "1700000000")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	logger := &recordingLogger{}
+	client := pushover.New(secretToken, pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()), pushover.WithLogger(logger))
+	if _, err := client.ValidateUser(context.Background(), &pushover.ValidateRequest{User: testUserKey}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var debug *logEntry
+	traces := 0
+	for i, e := range logger.entries {
+		for k, v := range e.fields {
+			if s := fmt.Sprint(v); strings.Contains(s, secretToken) || strings.Contains(s, testUserKey) {
+				t.Errorf("%s field %q leaks a secret: %s", e.level, k, s)
+			}
+		}
+		switch e.level {
+		case "DEBUG":
+			debug = &logger.entries[i]
+		case "TRACE":
+			traces++
+		}
+	}
+	if traces != 2 {
+		t.Errorf("expected request and response TRACE entries, got %d", traces)
+	}
+	if debug == nil {
+		t.Fatal("expected a DEBUG entry")
+	}
+	want := map[string]interface{}{
+		"http_method":          http.MethodPost,
+		"path":                 "/users/validate.json",
+		"status":               http.StatusOK,
+		"request_id":           fixtureResponse[pushover.ValidateResponse](t, "validate_user_licenses", 0).Request,
+		"rate_limit_remaining": "9999",
+	}
+	for k, v := range want {
+		if debug.fields[k] != v {
+			t.Errorf("DEBUG field %q = %v, want %v", k, debug.fields[k], v)
+		}
+	}
+	if _, ok := debug.fields["duration_ms"]; !ok {
+		t.Error("expected duration_ms in DEBUG entry")
+	}
+}
+
+func TestLogger_PathOmitsKeys(t *testing.T) {
+	body := fixtureBody(t, "group_membership", 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	logger := &recordingLogger{}
+	client := pushover.New(secretToken, pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()), pushover.WithLogger(logger))
+	if _, err := client.GetGroup(context.Background(), testGroupKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(logger.entries) == 0 {
+		t.Fatal("expected log entries")
+	}
+	for _, e := range logger.entries {
+		if path := fmt.Sprint(e.fields["path"]); path != "/groups/{key}.json" {
+			t.Errorf("%s %q path = %q, want /groups/{key}.json", e.level, e.msg, path)
+		}
+	}
+}
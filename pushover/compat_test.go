@@ -0,0 +1,91 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+)
+
+// These assignments fail to compile if an exported signature changes in a
+// way that would break callers.
+var (
+	_ pushover.API = (*pushover.Client)(nil)
+
+	_ func(string, ...pushover.Option) *pushover.Client = pushover.New
+	_ func(*http.Client) pushover.Option                = pushover.WithHTTPClient
+	_ func(string) pushover.Option                      = pushover.WithBaseURL
+	_ func(string) pushover.Option                      = pushover.WithUserAgent
+	_ func(pushover.RetryPolicy) pushover.Option        = pushover.WithRetry
+	_ func(float64, int) pushover.Option                = pushover.WithRateLimit
+	_ func(pushover.Limiter) pushover.Option            = pushover.WithRateLimiter
+	_ func(pushover.Logger) pushover.Option             = pushover.WithLogger
+	_ func(...pushover.Middleware) pushover.Option      = pushover.WithMiddleware
+	_ func(string, string) error                        = pushover.ValidateKey
+)
+
+func TestNew_Defaults(t *testing.T) {
+	client := pushover.New("tok")
+	if client == nil {
+		t.Fatal("expected a client")
+	}
+	if pushover.DefaultBaseURL != "https://api.pushover.net/1" {
+		t.Errorf("DefaultBaseURL changed to %q", pushover.DefaultBaseURL)
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	var gotPath, gotUA string
+	body := fixtureBody(t, "sounds_custom", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotUA = r.URL.Path, r.Header.Get("User-Agent")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok",
+		pushover.WithBaseURL(srv.URL+"/1/"),
+		pushover.WithHTTPClient(srv.Client()),
+		pushover.WithUserAgent("my-service/1.0"),
+	)
+	if _, err := client.GetSounds(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/1/sounds.json" {
+		t.Errorf("path = %q, want /1/sounds.json", gotPath)
+	}
+	if gotUA != "my-service/1.0" {
+		t.Errorf("User-Agent = %q, want my-service/1.0", gotUA)
+	}
+}
+
+func TestNew_DefaultUserAgent(t *testing.T) {
+	var gotUA string
+	body := fixtureBody(t, "emergency_receipt", 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUA = r.Header.Get("User-Agent")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()))
+	if _, err := client.CancelReceipt(context.Background(), testReceipt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotUA != pushover.DefaultUserAgent {
+		t.Errorf("User-Agent = %q, want %q", gotUA, pushover.DefaultUserAgent)
+	}
+}
+
+func TestNew_WithHTTPClientNotModified(t *testing.T) {
+	hc := &http.Client{}
+	_ = pushover.New("tok", pushover.WithHTTPClient(hc), pushover.WithRetry(pushover.DefaultRetryPolicy))
+	if hc.Transport != nil {
+		t.Error("New modified the caller's *http.Client")
+	}
+}
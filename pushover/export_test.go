@@ -0,0 +1,14 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover
+
+import "time"
+
+// SetMinReceiptPollInterval lowers the receipt poll floor so tests do not
+// wait seconds between polls. The returned function restores it.
+func SetMinReceiptPollInterval(d time.Duration) (restore func()) {
+	prev := minReceiptPollInterval
+	minReceiptPollInterval = d
+	return func() { minReceiptPollInterval = prev }
+}
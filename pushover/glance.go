@@ -0,0 +1,82 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"unicode/utf8"
+)
+
+// MaxGlanceTextLength is the longest title, text or subtext a glance accepts.
+const MaxGlanceTextLength = 100
+
+// GlanceRequest updates the glance data shown in a user's watch
+// complications and widgets. Fields left nil are not changed on the device;
+// at least one must be set.
+type GlanceRequest struct {
+	Token string
+	User  string
+	// Device limits the update to one of the user's devices.
+	Device  string
+	Title   *string
+	Text    *string
+	Subtext *string
+	Count   *int
+	// Percent is shown as a progress indicator, from 0 to 100.
+	Percent *int
+}
+
+// Validate checks req against the limits Pushover documents for glances.
+func (req *GlanceRequest) Validate() error {
+	if err := ValidateKey("user", req.User); err != nil {
+		return err
+	}
+	if req.Title == nil && req.Text == nil && req.Subtext == nil && req.Count == nil && req.Percent == nil {
+		return &ValidationError{Field: "glance", Reason: "at least one of title, text, subtext, count or percent must be set"}
+	}
+	for field, v := range map[string]*string{"title": req.Title, "text": req.Text, "subtext": req.Subtext} {
+		if v != nil && utf8.RuneCountInString(*v) > MaxGlanceTextLength {
+			return &ValidationError{Field: field, Reason: fmt.Sprintf("must be at most %d characters", MaxGlanceTextLength)}
+		}
+	}
+	if req.Percent != nil && (*req.Percent < 0 || *req.Percent > 100) {
+		return &ValidationError{Field: "percent", Reason: "must be between 0 and 100"}
+	}
+	return nil
+}
+
+// UpdateGlance pushes new glance data to a user's devices.
+func (c *Client) UpdateGlance(ctx context.Context, req *GlanceRequest) (*APIResponse, error) {
+	if req.Token == "" {
+		req.Token = c.token
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("token", req.Token)
+	params.Set("user", req.User)
+	if req.Device != "" {
+		params.Set("device", req.Device)
+	}
+	for field, v := range map[string]*string{"title": req.Title, "text": req.Text, "subtext": req.Subtext} {
+		if v != nil {
+			params.Set(field, *v)
+		}
+	}
+	if req.Count != nil {
+		params.Set("count", strconv.Itoa(*req.Count))
+	}
+	if req.Percent != nil {
+		params.Set("percent", strconv.Itoa(*req.Percent))
+	}
+	var resp APIResponse
+	if err := c.doPost(ctx, "/glances.json", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
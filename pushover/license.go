@@ -0,0 +1,100 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Platforms a license can be assigned to.
+const (
+	LicenseAndroid = "Android"
+	LicenseIOS     = "iOS"
+	LicenseDesktop = "Desktop"
+)
+
+// LicensePlatforms lists the platforms a license can be assigned to.
+var LicensePlatforms = []string{LicenseAndroid, LicenseIOS, LicenseDesktop}
+
+// LicenseAssignRequest assigns one of the application owner's prepaid
+// license credits to a user, identified by either user key or email address.
+type LicenseAssignRequest struct {
+	Token string
+	User  string
+	// Email assigns the license to the account with this address, creating
+	// one if needed.
+	Email string
+	// OS limits the license to one platform in LicensePlatforms. When empty,
+	// the license applies to the first platform the user activates.
+	OS string
+}
+
+// Validate checks that req names exactly one user and, if set, a known
+// platform.
+func (req *LicenseAssignRequest) Validate() error {
+	switch {
+	case req.User == "" && req.Email == "":
+		return &ValidationError{Field: "user", Reason: "either user or email must be set"}
+	case req.User != "" && req.Email != "":
+		return &ValidationError{Field: "user", Reason: "only one of user or email may be set"}
+	case req.User != "":
+		if err := ValidateKey("user", req.User); err != nil {
+			return err
+		}
+	default:
+		if err := validateEmail(req.Email); err != nil {
+			return err
+		}
+	}
+	if req.OS != "" && !slices.Contains(LicensePlatforms, req.OS) {
+		return &ValidationError{Field: "os", Reason: fmt.Sprintf("must be one of %s", strings.Join(LicensePlatforms, ", "))}
+	}
+	return nil
+}
+
+// LicenseCreditsResponse is the number of license credits left to assign.
+type LicenseCreditsResponse struct {
+	APIResponse
+	Credits int `json:"credits"`
+}
+
+// AssignLicense spends one license credit on a user.
+func (c *Client) AssignLicense(ctx context.Context, req *LicenseAssignRequest) (*APIResponse, error) {
+	if req.Token == "" {
+		req.Token = c.token
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("token", req.Token)
+	if req.User != "" {
+		params.Set("user", req.User)
+	} else {
+		params.Set("email", req.Email)
+	}
+	if req.OS != "" {
+		params.Set("os", req.OS)
+	}
+	var resp APIResponse
+	if err := c.doPost(ctx, "/licenses/assign.json", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetLicenseCredits retrieves the number of license credits the
+// application's owner has left to assign.
+func (c *Client) GetLicenseCredits(ctx context.Context) (*LicenseCreditsResponse, error) {
+	path := fmt.Sprintf("/licenses.json?token=%s", url.QueryEscape(c.token))
+	var resp LicenseCreditsResponse
+	if err := c.doGet(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,135 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Logger receives structured events describing API traffic. Debug is called
+// once per HTTP attempt with a summary; Trace is called with the redacted
+// request parameters and response body. Fields named "token" and "user" hold
+// request parameters and are already masked, but implementations may mask
+// them again.
+type Logger interface {
+	Debug(ctx context.Context, msg string, fields map[string]interface{})
+	Trace(ctx context.Context, msg string, fields map[string]interface{})
+}
+
+// WithLogger sends API traffic logs to l through the Logging middleware.
+func WithLogger(l Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// rateLimitHeaders are the application usage headers returned by Pushover.
+var rateLimitHeaders = map[string]string{
+	"X-Limit-App-Limit":     "rate_limit",
+	"X-Limit-App-Remaining": "rate_limit_remaining",
+	"X-Limit-App-Reset":     "rate_limit_reset",
+}
+
+// Logging returns middleware that logs every HTTP attempt to l: a summary at
+// DEBUG and the redacted request parameters and response body at TRACE.
+func Logging(l Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			logRequest(ctx, l, req)
+
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				logAttempt(ctx, l, req, nil, "", time.Since(start), err)
+				return nil, err
+			}
+
+			// Buffer the body so it can be logged and still be read by the client.
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			if err != nil {
+				logAttempt(ctx, l, req, resp, "", time.Since(start), err)
+				return nil, err
+			}
+			l.Trace(ctx, "Received Pushover API response", map[string]interface{}{
+				"http_method":   req.Method,
+				"path":          endpointName(req.URL.Path),
+				"status":        resp.StatusCode,
+				"response_body": redactString(string(body)),
+			})
+			logAttempt(ctx, l, req, resp, requestID(body), time.Since(start), nil)
+			return resp, nil
+		})
+	}
+}
+
+// logRequest logs the redacted parameters of req at TRACE.
+func logRequest(ctx context.Context, l Logger, req *http.Request) {
+	params := req.URL.Query()
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			if raw, err := io.ReadAll(body); err == nil {
+				if form, err := url.ParseQuery(string(raw)); err == nil {
+					params = form
+				}
+			}
+		}
+	}
+
+	fields := map[string]interface{}{
+		"http_method": req.Method,
+		"path":        endpointName(req.URL.Path),
+	}
+	for k := range params {
+		switch k {
+		case "token", "user":
+			fields[k] = mask(params.Get(k))
+		default:
+			fields[k] = redactString(params.Get(k))
+		}
+	}
+	l.Trace(ctx, "Sending Pushover API request", fields)
+}
+
+// logAttempt logs a summary of an HTTP attempt at DEBUG. resp is nil when
+// the request could not be sent.
+func logAttempt(ctx context.Context, l Logger, req *http.Request, resp *http.Response, requestID string, elapsed time.Duration, err error) {
+	fields := map[string]interface{}{
+		"http_method": req.Method,
+		"path":        endpointName(req.URL.Path),
+		"duration_ms": elapsed.Milliseconds(),
+	}
+	if requestID != "" {
+		fields["request_id"] = requestID
+	}
+	if resp != nil {
+		fields["status"] = resp.StatusCode
+		for header, key := range rateLimitHeaders {
+			if v := resp.Header.Get(header); v != "" {
+				fields[key] = v
+			}
+		}
+	}
+	if err != nil {
+		fields["error"] = redactString(err.Error())
+	}
+	l.Debug(ctx, "Pushover API call", fields)
+}
+
+// requestID extracts the request field from a Pushover response body.
+func requestID(body []byte) string {
+	var r struct {
+		Request string `json:"request"`
+	}
+	_ = json.Unmarshal(body, &r)
+	return r.Request
+}
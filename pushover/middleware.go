@@ -0,0 +1,263 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Middleware wraps the transport used for every HTTP attempt made by a
+// Client, for example to add headers, sign requests or inject faults.
+//
+// The client composes its transport in a fixed order, outermost first:
+//
+//  1. Retry (WithRetry), which re-sends failed attempts through the layers below.
+//  2. Rate limiting (WithRateLimiter), so that each retried attempt waits for a
+//     token. The first attempt waits before the *http.Client is called, so
+//     time spent queueing does not count against its Timeout.
+//  3. Logging (WithLogger), so that each attempt is logged with its outcome.
+//  4. Middleware passed to WithMiddleware, the first one outermost.
+//  5. The transport of the *http.Client given to the constructor.
+//
+// Custom middleware therefore sees every retried attempt, and its effects,
+// such as injected faults, are visible to logging and retries.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithMiddleware appends middleware to the client's transport chain.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// Limiter blocks until a request may be sent. *rate.Limiter from
+// golang.org/x/time/rate satisfies it.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// WithRateLimiter makes every HTTP attempt wait for l. Share one Limiter
+// between clients to give them a common budget.
+//
+// The wait for a call's first attempt happens before the request is handed to
+// the *http.Client and is bounded only by the call's context, so a queue of
+// requests does not run into the client's Timeout.
+func WithRateLimiter(l Limiter) Option {
+	return func(c *Client) {
+		c.limiter = l
+	}
+}
+
+// WithRateLimit limits the client to rps requests per second on average,
+// allowing bursts of up to burst requests. Requests over the limit block until
+// a token is available or their context is done. To share a budget between
+// several clients, create one *rate.Limiter and pass it to WithRateLimiter.
+func WithRateLimit(rps float64, burst int) Option {
+	return WithRateLimiter(rate.NewLimiter(rate.Limit(rps), burst))
+}
+
+// RateLimit returns middleware that waits for l before each request. Waiting
+// stops with the context's error when the request's context is done.
+func RateLimit(l Limiter) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if err := l.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// limiterTokenKey is the context key under which Client.do records that the
+// first attempt of a call already holds a rate limiter token.
+type limiterTokenKey struct{}
+
+// rateLimitRetries is like RateLimit, but lets through the first attempt of a
+// call whose token Client.do already waited for.
+func rateLimitRetries(l Limiter) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if held, ok := req.Context().Value(limiterTokenKey{}).(*atomic.Bool); ok && held.CompareAndSwap(true, false) {
+				return next.RoundTrip(req)
+			}
+			if err := l.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// RetryPolicy controls how failed attempts are retried.
+//
+// A request the server turned away with 429 Too Many Requests or 503 Service
+// Unavailable is retried after the delay in its Retry-After header, or after
+// the backoff when there is none. If Retry-After asks for longer than
+// MaxBackoff, the response is returned instead.
+//
+// Other failures are retried only when repeating the request cannot send a
+// message twice: GET requests are retried after a network error or any 5xx
+// status, while POST requests are retried only after a network error that
+// happened before the request was written to the connection, such as a
+// failed dial. API errors such as an invalid key are never retried, since
+// repeating them cannot succeed.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry. It doubles for every
+	// further retry, up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy follows Pushover's guidance to wait at least five
+// seconds before retrying a failed request.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  5 * time.Second,
+	MaxBackoff:  30 * time.Second,
+}
+
+// WithRetry retries failed attempts according to p.
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = &p
+	}
+}
+
+// Retry returns middleware that retries failed attempts according to p.
+func Retry(p RetryPolicy) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			backoff := p.MinBackoff
+			for attempt := 1; ; attempt++ {
+				attemptReq, err := rewind(req)
+				if err != nil {
+					return nil, err
+				}
+				var wrote atomic.Bool
+				attemptReq = attemptReq.WithContext(httptrace.WithClientTrace(attemptReq.Context(), &httptrace.ClientTrace{
+					WroteHeaders: func() { wrote.Store(true) },
+				}))
+				resp, err := next.RoundTrip(attemptReq)
+				if attempt >= p.MaxAttempts || !retryable(req.Method, wrote.Load(), resp, err) {
+					return resp, err
+				}
+				delay := backoff
+				if resp != nil {
+					if after, ok := retryAfter(resp); ok {
+						if after > max(p.MaxBackoff, p.MinBackoff) {
+							return resp, nil
+						}
+						delay = max(delay, after)
+					}
+					_, _ = io.Copy(io.Discard, resp.Body)
+					resp.Body.Close()
+				}
+
+				timer := time.NewTimer(delay)
+				select {
+				case <-req.Context().Done():
+					timer.Stop()
+					return nil, req.Context().Err()
+				case <-timer.C:
+				}
+				backoff = min(backoff*2, max(p.MaxBackoff, p.MinBackoff))
+			}
+		})
+	}
+}
+
+// rewind returns a copy of req with a fresh body, so it can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+// retryable reports whether an attempt failed in a way worth retrying.
+// wrote reports whether any of the request reached the connection.
+func retryable(method string, wrote bool, resp *http.Response, err error) bool {
+	idempotent := method == http.MethodGet || method == http.MethodHead
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return idempotent || !wrote
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusServiceUnavailable:
+		// The server turned the request away without acting on it.
+		return true
+	case resp.StatusCode >= 500:
+		return idempotent
+	}
+	return false
+}
+
+// retryAfter returns the delay requested by resp's Retry-After header, given
+// either in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// transport composes the client's transport chain around base in the order
+// documented on Middleware.
+func (c *Client) transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	rt := base
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		rt = c.middleware[i](rt)
+	}
+	if c.logger != nil {
+		rt = Logging(c.logger)(rt)
+	}
+	if c.limiter != nil {
+		rt = rateLimitRetries(c.limiter)(rt)
+	}
+	if c.tracer != nil {
+		rt = countingTransport{next: rt}
+	}
+	if c.retry != nil {
+		rt = Retry(*c.retry)(rt)
+	}
+	return rt
+}
@@ -0,0 +1,389 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+)
+
+var fastRetry = pushover.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+// recordingLimiter counts Wait calls and never blocks.
+type recordingLimiter struct {
+	waits atomic.Int32
+	order *[]string
+}
+
+func (l *recordingLimiter) Wait(ctx context.Context) error {
+	l.waits.Add(1)
+	if l.order != nil {
+		*l.order = append(*l.order, "ratelimit")
+	}
+	return ctx.Err()
+}
+
+// tag returns middleware that records its name each time it runs.
+func tag(name string, order *[]string) pushover.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return pushover.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			*order = append(*order, name)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+func TestMiddleware_CustomHeader(t *testing.T) {
+	body := fixtureBody(t, "sounds_custom", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Gateway-Auth") != "secret" {
+			t.Errorf("expected gateway header, got %q", r.Header.Get("X-Gateway-Auth"))
+		}
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	auth := func(next http.RoundTripper) http.RoundTripper {
+		return pushover.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Gateway-Auth", "secret")
+			return next.RoundTrip(req)
+		})
+	}
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()), pushover.WithMiddleware(auth))
+	if _, err := client.GetSounds(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMiddleware_Order(t *testing.T) {
+	var order []string
+	var calls atomic.Int32
+	body := fixtureBody(t, "emergency_receipt", 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		order = append(order, "server")
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	logger := &recordingLogger{}
+	limiter := &recordingLimiter{order: &order}
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()),
+		pushover.WithMiddleware(tag("first", &order), tag("second", &order)),
+		pushover.WithLogger(logger),
+		pushover.WithRateLimiter(limiter),
+		pushover.WithRetry(fastRetry),
+	)
+	if _, err := client.CancelReceipt(context.Background(), testReceipt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"ratelimit", "first", "second", "server", "ratelimit", "first", "second", "server"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+	debug := 0
+	for _, e := range logger.entries {
+		if e.level == "DEBUG" {
+			debug++
+		}
+	}
+	if debug != 2 {
+		t.Errorf("expected one DEBUG entry per attempt, got %d", debug)
+	}
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()), pushover.WithRetry(fastRetry))
+	if _, err := client.GetSounds(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestRetry_APIErrorNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	body := fixtureBody(t, "send_invalid_user", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()), pushover.WithRetry(fastRetry))
+	_, err := client.SendMessage(context.Background(), &pushover.MessageRequest{User: testUserKey, Message: "hi"})
+	var apiErr *pushover.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls.Load())
+	}
+}
+
+func TestRetry_PostServerErrorNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()), pushover.WithRetry(fastRetry))
+	if _, err := client.SendMessage(context.Background(), &pushover.MessageRequest{User: testUserKey, Message: "hi"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("a message that may have been sent was retried: %d attempts", calls.Load())
+	}
+}
+
+func TestRetry_DroppedConnection(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()), pushover.WithRetry(fastRetry))
+
+	if _, err := client.SendMessage(context.Background(), &pushover.MessageRequest{User: testUserKey, Message: "hi"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("POST written before the connection dropped was retried: %d attempts", calls.Load())
+	}
+
+	calls.Store(0)
+	if _, err := client.GetSounds(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected GET to be retried 3 times, got %d", calls.Load())
+	}
+}
+
+func TestRetry_PostRetriedWhenNotSent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	url := srv.URL
+	srv.Close()
+
+	var attempts []string
+	client := pushover.New("tok", pushover.WithBaseURL(url),
+		pushover.WithMiddleware(tag("attempt", &attempts)),
+		pushover.WithRetry(fastRetry),
+	)
+	if _, err := client.SendMessage(context.Background(), &pushover.MessageRequest{User: testUserKey, Message: "hi"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(attempts) != 3 {
+		t.Errorf("expected a failed dial to be retried 3 times, got %d", len(attempts))
+	}
+}
+
+func TestRetry_HonoursRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	body := fixtureBody(t, "send_message", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	policy := pushover.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Second}
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()), pushover.WithRetry(policy))
+
+	start := time.Now()
+	if _, err := client.SendMessage(context.Background(), &pushover.MessageRequest{User: testUserKey, Message: "hi"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, before Retry-After elapsed", elapsed)
+	}
+}
+
+func TestRetry_RetryAfterBeyondMaxBackoff(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+		// No fixture records a 429; the body is the API's documented one.
+		_, _ = w.Write([]byte(`{"status":0,"errors":["application is over its message limit"]}`))
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()), pushover.WithRetry(fastRetry))
+	_, err := client.SendMessage(context.Background(), &pushover.MessageRequest{User: testUserKey, Message: "hi"})
+	var apiErr *pushover.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != http.StatusTooManyRequests {
+		t.Fatalf("expected a 429 *APIError, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls.Load())
+	}
+}
+
+func TestRetry_StopsOnContextCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	slow := pushover.RetryPolicy{MaxAttempts: 5, MinBackoff: time.Hour, MaxBackoff: time.Hour}
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()), pushover.WithRetry(slow))
+
+	start := time.Now()
+	_, err := client.GetSounds(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("retry did not stop when the context was done")
+	}
+}
+
+func TestRetry_FaultInjectionCountedInSpan(t *testing.T) {
+	body := fixtureBody(t, "emergency_receipt", 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	// Fail the first attempt before it reaches the network.
+	var injected atomic.Bool
+	fault := func(next http.RoundTripper) http.RoundTripper {
+		return pushover.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if injected.CompareAndSwap(false, true) {
+				return nil, errors.New("injected fault")
+			}
+			return next.RoundTrip(req)
+		})
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()),
+		pushover.WithMiddleware(fault),
+		pushover.WithRetry(fastRetry),
+		pushover.WithTracerProvider(tp),
+	)
+	if _, err := client.CancelReceipt(context.Background(), testReceipt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if got := spanAttrs(spans[0])["pushover.retry_attempt"].AsInt64(); got != 1 {
+		t.Errorf("retry attempt = %d, want 1", got)
+	}
+}
+
+func TestRateLimit_Blocks(t *testing.T) {
+	body := fixtureBody(t, "sounds_custom", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()), pushover.WithRateLimit(20, 1))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetSounds(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// The first call uses the burst; the other two wait 50ms each.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected calls to be spaced out, took %v", elapsed)
+	}
+}
+
+func TestRateLimit_StopsOnContextCancel(t *testing.T) {
+	var calls atomic.Int32
+	body := fixtureBody(t, "sounds_custom", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()), pushover.WithRateLimit(0.001, 1))
+	if _, err := client.GetSounds(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.GetSounds(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected the cancelled call not to be sent, got %d calls", calls.Load())
+	}
+}
+
+func TestRateLimit_QueueingExcludedFromTimeout(t *testing.T) {
+	body := fixtureBody(t, "sounds_custom", 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	httpClient := srv.Client()
+	httpClient.Timeout = 50 * time.Millisecond
+	// The second call waits 200ms for a token, well past the timeout.
+	client := pushover.New("tok", pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(httpClient), pushover.WithRateLimit(5, 1))
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetSounds(context.Background()); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i+1, err)
+		}
+	}
+}
@@ -0,0 +1,527 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+// Package pushovertest provides a stateful, in-process fake of the Pushover
+// API for tests that should run without network access or real credentials.
+//
+// The fake keeps messages, emergency receipts, glances, groups, users,
+// subscriptions, teams, licenses and the monthly message limit in memory and applies the same
+// validation rules as the real API, so that invalid requests fail the way
+// they would in production:
+//
+//	srv := pushovertest.NewServer()
+//	defer srv.Close()
+//	client := pushover.New(pushovertest.Token, pushover.WithBaseURL(srv.URL))
+//
+// Tests can inspect and change the state directly, for example to
+// acknowledge a receipt, and queue failures with FailNext.
+package pushovertest
+
+import (
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+)
+
+// Credentials registered in every new Fake.
+const (
+	// Token is a registered application token.
+	Token = "aFakePushoverApplicationToken1"
+	// UserKey is a registered user with the devices in UserDevices.
+	UserKey = "uFakePushoverUserKey0000000001"
+	// OtherUserKey is a second registered user with the devices in UserDevices.
+	OtherUserKey = "uFakePushoverUserKey0000000002"
+	// GroupKey is a registered, initially empty, delivery group.
+	GroupKey = "gFakePushoverGroupKey000000001"
+	// SubscriptionCode is a registered subscription of the Token application.
+	SubscriptionCode = "fake-subscription"
+	// TeamToken is the API token of a registered, initially empty, team.
+	TeamToken = "tFakePushoverTeamToken00000001"
+)
+
+// UserDevices are the devices registered for UserKey and OtherUserKey.
+var UserDevices = []string{"iphone", "pixel"}
+
+// DefaultLimit is the monthly message limit of a new Fake.
+const DefaultLimit = 10000
+
+// DefaultCredits is the number of license credits of a new Fake.
+const DefaultCredits = 10
+
+// ErrNotFound is returned by Fake methods given an unknown receipt, group or
+// group member.
+var ErrNotFound = errors.New("pushovertest: not found")
+
+// Message is a message accepted by the fake.
+type Message struct {
+	Request   string    `json:"request"`
+	Receipt   string    `json:"receipt,omitempty"`
+	Users     []string  `json:"users"`
+	Message   string    `json:"message"`
+	Title     string    `json:"title,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	URLTitle  string    `json:"url_title,omitempty"`
+	Priority  int       `json:"priority"`
+	Sound     string    `json:"sound,omitempty"`
+	Device    string    `json:"device,omitempty"`
+	HTML      bool      `json:"html,omitempty"`
+	Monospace bool      `json:"monospace,omitempty"`
+	TTL       int       `json:"ttl,omitempty"`
+	Timestamp int64     `json:"timestamp,omitempty"`
+	Retry     int       `json:"retry,omitempty"`
+	Expire    int       `json:"expire,omitempty"`
+	Callback  string    `json:"callback,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	SentAt    time.Time `json:"sent_at"`
+}
+
+// Receipt is the state of an emergency-priority message.
+type Receipt struct {
+	Receipt              string    `json:"receipt"`
+	Request              string    `json:"request"`
+	Users                []string  `json:"users"`
+	Tags                 []string  `json:"tags,omitempty"`
+	Callback             string    `json:"callback,omitempty"`
+	ExpiresAt            time.Time `json:"expires_at"`
+	Acknowledged         bool      `json:"acknowledged"`
+	AcknowledgedAt       time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy       string    `json:"acknowledged_by,omitempty"`
+	AcknowledgedByDevice string    `json:"acknowledged_by_device,omitempty"`
+	// CallbackHeld stops acknowledgement from calling the callback URL
+	// until CallBack is called. See HoldCallback.
+	CallbackHeld bool      `json:"callback_held,omitempty"`
+	CalledBackAt time.Time `json:"called_back_at,omitempty"`
+	Canceled     bool      `json:"canceled"`
+}
+
+// Expired reports whether the receipt stopped retrying at now.
+func (r Receipt) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// Glance is the glance data last pushed to a user, or to one of their
+// devices. Fields that were never set are empty, or nil for Count and Percent.
+type Glance struct {
+	User      string    `json:"user"`
+	Device    string    `json:"device,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Text      string    `json:"text,omitempty"`
+	Subtext   string    `json:"subtext,omitempty"`
+	Count     *int      `json:"count,omitempty"`
+	Percent   *int      `json:"percent,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Subscriber is a user key migrated to a subscription.
+type Subscriber struct {
+	// Key is the subscription-specific user key.
+	Key          string `json:"key"`
+	Subscription string `json:"subscription"`
+	User         string `json:"user"`
+	Device       string `json:"device,omitempty"`
+	Sound        string `json:"sound,omitempty"`
+}
+
+// TeamMember is a member of a team.
+type TeamMember struct {
+	pushover.TeamMember
+	// Instant reports whether the account was created without waiting for
+	// the user to accept an invitation.
+	Instant bool
+}
+
+type team struct {
+	name    string
+	members []TeamMember
+}
+
+// License is a license assigned from the fake's credits, to either a user
+// key or an email address.
+type License struct {
+	User       string    `json:"user,omitempty"`
+	Email      string    `json:"email,omitempty"`
+	OS         string    `json:"os,omitempty"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+type group struct {
+	name    string
+	members []pushover.GroupMember
+}
+
+type failure struct {
+	status int
+	errors []string
+}
+
+// Fake is a stateful Pushover API implemented as an http.Handler. Its routes
+// are served under /1, like the real API. The zero value is not usable; call
+// NewFake.
+type Fake struct {
+	mu        sync.Mutex
+	mux       *http.ServeMux
+	now       func() time.Time
+	apps      map[string]bool
+	users     map[string][]string
+	groups    map[string]*group
+	messages  []Message
+	receipts  map[string]*Receipt
+	glances   map[string]*Glance
+	limit     int
+	remaining int
+	failures  map[string][]failure
+
+	// subscriptions maps subscription codes to their subscribers, by the
+	// subscribers' original user keys.
+	subscriptions map[string]map[string]*Subscriber
+	// teams maps team API tokens to their teams.
+	teams map[string]*team
+
+	credits  int
+	licenses []License
+}
+
+// NewFake returns a fake with Token, UserKey, OtherUserKey, GroupKey,
+// SubscriptionCode and TeamToken registered, DefaultLimit messages remaining
+// and DefaultCredits license credits.
+func NewFake() *Fake {
+	f := &Fake{
+		now:       time.Now,
+		apps:      map[string]bool{Token: true},
+		users:     map[string][]string{},
+		groups:    map[string]*group{},
+		receipts:  map[string]*Receipt{},
+		glances:   map[string]*Glance{},
+		limit:     DefaultLimit,
+		remaining: DefaultLimit,
+		failures:  map[string][]failure{},
+		credits:   DefaultCredits,
+	}
+	f.AddUser(UserKey, UserDevices...)
+	f.AddUser(OtherUserKey, UserDevices...)
+	f.AddGroup(GroupKey, "Fake Group")
+	f.AddSubscription(SubscriptionCode)
+	f.AddTeam(TeamToken, "Fake Team")
+	f.routes()
+	return f
+}
+
+// ServeHTTP serves the fake API.
+func (f *Fake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mux.ServeHTTP(w, r)
+}
+
+// AddApplication registers an application token.
+func (f *Fake) AddApplication(token string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.apps[token] = true
+}
+
+// AddUser registers a user key with the given device names.
+func (f *Fake) AddUser(key string, devices ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.users[key] = slices.Clone(devices)
+}
+
+// AddGroup registers an empty delivery group.
+func (f *Fake) AddGroup(key, name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.groups[key] = &group{name: name}
+}
+
+// AddSubscription registers a subscription code.
+func (f *Fake) AddSubscription(code string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.subscriptions == nil {
+		f.subscriptions = map[string]map[string]*Subscriber{}
+	}
+	if f.subscriptions[code] == nil {
+		f.subscriptions[code] = map[string]*Subscriber{}
+	}
+}
+
+// Subscriber returns the subscriber with the subscription-specific user key.
+func (f *Fake) Subscriber(key string) (Subscriber, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, subscribers := range f.subscriptions {
+		for _, s := range subscribers {
+			if s.Key == key {
+				return *s, nil
+			}
+		}
+	}
+	return Subscriber{}, ErrNotFound
+}
+
+// AddTeam registers an empty team with the given API token.
+func (f *Fake) AddTeam(token, name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.teams == nil {
+		f.teams = map[string]*team{}
+	}
+	f.teams[token] = &team{name: name}
+}
+
+// Team returns the name and members of the team with the given API token.
+func (f *Fake) Team(token string) (string, []TeamMember, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.teams[token]
+	if !ok {
+		return "", nil, ErrNotFound
+	}
+	return t.name, slices.Clone(t.members), nil
+}
+
+// GroupKeys returns the keys of every registered group, sorted.
+func (f *Fake) GroupKeys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := make([]string, 0, len(f.groups))
+	for k := range f.groups {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Group returns the name and members of a group.
+func (f *Fake) Group(key string) (string, []pushover.GroupMember, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	g, ok := f.groups[key]
+	if !ok {
+		return "", nil, ErrNotFound
+	}
+	return g.name, slices.Clone(g.members), nil
+}
+
+// AddGroupMember adds m to a group, bypassing the API's validation, for
+// example to simulate a change made outside Terraform.
+func (f *Fake) AddGroupMember(key string, m pushover.GroupMember) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	g, ok := f.groups[key]
+	if !ok {
+		return ErrNotFound
+	}
+	g.members = append(g.members, m)
+	return nil
+}
+
+// RemoveGroupMember removes a member from a group, bypassing the API.
+func (f *Fake) RemoveGroupMember(key, user, device string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	g, ok := f.groups[key]
+	if !ok {
+		return ErrNotFound
+	}
+	i := g.member(user, device)
+	if i < 0 {
+		return ErrNotFound
+	}
+	g.members = slices.Delete(g.members, i, i+1)
+	return nil
+}
+
+// Messages returns the messages accepted so far, oldest first.
+func (f *Fake) Messages() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.messages)
+}
+
+// Receipts returns every emergency receipt issued so far.
+func (f *Fake) Receipts() []Receipt {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]Receipt, 0, len(f.receipts))
+	for _, r := range f.receipts {
+		out = append(out, *r)
+	}
+	slices.SortFunc(out, func(a, b Receipt) int { return a.ExpiresAt.Compare(b.ExpiresAt) })
+	return out
+}
+
+// Receipt returns the state of an emergency receipt.
+func (f *Fake) Receipt(id string) (Receipt, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.receipts[id]
+	if !ok {
+		return Receipt{}, ErrNotFound
+	}
+	return *r, nil
+}
+
+// Glance returns the glance data pushed to user for device, where an empty
+// device means updates sent to all of the user's devices.
+func (f *Fake) Glance(user, device string) (Glance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	g, ok := f.glances[glanceKey(user, device)]
+	if !ok {
+		return Glance{}, ErrNotFound
+	}
+	return *g, nil
+}
+
+func glanceKey(user, device string) string {
+	return user + "/" + device
+}
+
+// Acknowledge marks a receipt as acknowledged by user on device, as if the
+// recipient had tapped the notification.
+func (f *Fake) Acknowledge(id, user, device string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.receipts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	r.Acknowledged = true
+	r.AcknowledgedAt = f.now()
+	r.AcknowledgedBy = user
+	r.AcknowledgedByDevice = device
+	// Pushover calls the callback URL as soon as a receipt is acknowledged.
+	if r.Callback != "" && !r.CallbackHeld {
+		r.CalledBackAt = r.AcknowledgedAt
+	}
+	return nil
+}
+
+// HoldCallback makes the acknowledgement of a receipt wait for CallBack
+// before its callback URL is reported as called, as when Pushover is slow to
+// reach the URL.
+func (f *Fake) HoldCallback(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.receipts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	r.CallbackHeld = true
+	return nil
+}
+
+// CallBack marks the callback URL of a receipt as called.
+func (f *Fake) CallBack(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.receipts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	r.CalledBackAt = f.now()
+	return nil
+}
+
+// Expire makes a receipt stop retrying immediately.
+func (f *Fake) Expire(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.receipts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	r.ExpiresAt = f.now()
+	return nil
+}
+
+// SetLimit sets the monthly message limit and the number of messages left.
+// Once remaining reaches zero, messages are rejected with 429 Too Many
+// Requests.
+func (f *Fake) SetLimit(limit, remaining int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.limit, f.remaining = limit, remaining
+}
+
+// SetCredits sets the number of license credits left to assign. Once it
+// reaches zero, license assignments are rejected.
+func (f *Fake) SetCredits(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.credits = n
+}
+
+// Licenses returns the licenses assigned so far, oldest first.
+func (f *Fake) Licenses() []License {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.licenses)
+}
+
+// FailNext makes the next request to endpoint fail with status. Calls are
+// queued, so calling FailNext twice fails the next two requests. endpoint is
+// the API path after /1/ with keys written as placeholders, such as
+// "messages.json", "receipts/{receipt}.json", "receipts/{receipt}/cancel.json",
+// "receipts/cancel_by_tag/{tag}.json", "groups/{group}.json",
+// "groups/{group}/add_user.json", "glances.json",
+// "subscriptions/migrate.json", "teams.json", "teams/add_user.json",
+// "teams/remove_user.json", "licenses.json", "licenses/assign.json",
+// "sounds.json", "users/validate.json" or "apps/limits.json".
+//
+// A status below 500 produces a Pushover error response listing errs; a 5xx
+// status produces a body that is not JSON, as an overloaded server might.
+func (f *Fake) FailNext(endpoint string, status int, errs ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[endpoint] = append(f.failures[endpoint], failure{status: status, errors: errs})
+}
+
+// member returns the index of the member matching user and device, or -1.
+func (g *group) member(user, device string) int {
+	return slices.IndexFunc(g.members, func(m pushover.GroupMember) bool {
+		return m.User == user && m.Device == device
+	})
+}
+
+// member returns the index of the member with the given email address, or -1.
+// Email addresses are compared case-insensitively.
+func (t *team) member(email string) int {
+	return slices.IndexFunc(t.members, func(m TeamMember) bool {
+		return strings.EqualFold(m.Email, email)
+	})
+}
+
+// keyAlphabet is the character set of generated keys.
+const keyAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// newKey returns a random key in Pushover's format.
+func newKey() string {
+	b := make([]byte, 30)
+	_, _ = rand.Read(b)
+	for i := range b {
+		b[i] = keyAlphabet[int(b[i])%len(keyAlphabet)]
+	}
+	return string(b)
+}
+
+// newRequestID returns a random request ID formatted like Pushover's UUIDs.
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	const hex = "0123456789abcdef"
+	out := make([]byte, 0, 36)
+	for i, c := range b {
+		if i == 4 || i == 6 || i == 8 || i == 10 {
+			out = append(out, '-')
+		}
+		out = append(out, hex[c>>4], hex[c&0xf])
+	}
+	return string(out)
+}
@@ -0,0 +1,397 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushovertest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
+)
+
+func newClient(t *testing.T) (*pushovertest.Server, *pushover.Client) {
+	t.Helper()
+	srv := pushovertest.NewServer()
+	t.Cleanup(srv.Close)
+	return srv, pushover.New(pushovertest.Token, pushover.WithBaseURL(srv.URL))
+}
+
+func apiErrors(t *testing.T, err error) string {
+	t.Helper()
+	var apiErr *pushover.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *pushover.APIError, got %v", err)
+	}
+	return strings.Join(apiErr.Errors, "; ")
+}
+
+func TestFake_SendMessage(t *testing.T) {
+	srv, client := newClient(t)
+	resp, err := client.SendMessage(context.Background(), &pushover.MessageRequest{
+		User: pushovertest.UserKey, Message: "hello", Device: "iphone", Sound: "siren",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msgs := srv.Messages()
+	if len(msgs) != 1 || msgs[0].Request != resp.Request || msgs[0].Message != "hello" || msgs[0].Sound != "siren" {
+		t.Fatalf("unexpected messages: %+v", msgs)
+	}
+	if resp.Receipt != "" {
+		t.Errorf("expected no receipt for a normal-priority message, got %q", resp.Receipt)
+	}
+}
+
+func TestFake_ValidationRules(t *testing.T) {
+	cases := []struct {
+		name string
+		req  pushover.MessageRequest
+		want string
+	}{
+		{"unknown user", pushover.MessageRequest{User: "uUnknownUserKey000000000000000", Message: "hi"}, "not a valid user"},
+		{"blank message", pushover.MessageRequest{User: pushovertest.UserKey, Message: " "}, "message cannot be blank"},
+		{"message too long", pushover.MessageRequest{User: pushovertest.UserKey, Message: strings.Repeat("m", 1025)}, "message is too long"},
+		{"unknown sound", pushover.MessageRequest{User: pushovertest.UserKey, Message: "hi", Sound: "kazoo"}, "sound is invalid"},
+		{"unknown device", pushover.MessageRequest{User: pushovertest.UserKey, Message: "hi", Device: "toaster"}, "not valid for user"},
+		{"html and monospace", pushover.MessageRequest{User: pushovertest.UserKey, Message: "hi", HTML: 1, Monospace: 1}, "cannot both be set"},
+		{"retry too short", pushover.MessageRequest{User: pushovertest.UserKey, Message: "hi", Priority: 2, Retry: 10, Expire: 60}, "at least 30 seconds"},
+		{"expire too long", pushover.MessageRequest{User: pushovertest.UserKey, Message: "hi", Priority: 2, Retry: 30, Expire: 10801}, "at most 10800 seconds"},
+	}
+	_, client := newClient(t)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := client.SendMessage(context.Background(), &tc.req)
+			if got := apiErrors(t, err); !strings.Contains(got, tc.want) {
+				t.Errorf("errors = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFake_InvalidToken(t *testing.T) {
+	srv, _ := newClient(t)
+	client := pushover.New("aUnknownApplicationToken000000", pushover.WithBaseURL(srv.URL))
+	_, err := client.GetSounds(context.Background())
+	if got := apiErrors(t, err); !strings.Contains(got, "application token is invalid") {
+		t.Errorf("errors = %q", got)
+	}
+}
+
+func TestFake_ReceiptLifecycle(t *testing.T) {
+	srv, client := newClient(t)
+	ctx := context.Background()
+	resp, err := client.SendMessage(ctx, &pushover.MessageRequest{
+		User: pushovertest.UserKey, Message: "wake up", Priority: 2, Retry: 30, Expire: 3600,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	receipt, err := client.GetReceipt(ctx, resp.Receipt)
+	if err != nil || receipt.Acknowledged != 0 || receipt.Expired != 0 {
+		t.Fatalf("unexpected receipt %+v, err %v", receipt, err)
+	}
+	if err := srv.Acknowledge(resp.Receipt, pushovertest.UserKey, "iphone"); err != nil {
+		t.Fatal(err)
+	}
+	receipt, err = client.GetReceipt(ctx, resp.Receipt)
+	if err != nil || receipt.Acknowledged != 1 || receipt.AcknowledgedByDevice != "iphone" {
+		t.Fatalf("unexpected receipt %+v, err %v", receipt, err)
+	}
+
+	if _, err := client.CancelReceipt(ctx, resp.Receipt); err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := srv.Receipt(resp.Receipt); !r.Canceled {
+		t.Error("expected the receipt to be canceled")
+	}
+}
+
+func TestFake_ExpireReceipt(t *testing.T) {
+	srv, client := newClient(t)
+	ctx := context.Background()
+	resp, err := client.SendMessage(ctx, &pushover.MessageRequest{
+		User: pushovertest.UserKey, Message: "wake up", Priority: 2, Retry: 30, Expire: 3600,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := srv.Expire(resp.Receipt); err != nil {
+		t.Fatal(err)
+	}
+	if receipt, err := client.GetReceipt(ctx, resp.Receipt); err != nil || receipt.Expired != 1 {
+		t.Fatalf("unexpected receipt %+v, err %v", receipt, err)
+	}
+}
+
+func TestFake_CancelByTag(t *testing.T) {
+	srv := pushovertest.NewServer()
+	defer srv.Close()
+
+	post := func(path, body string) *http.Response {
+		t.Helper()
+		resp, err := http.Post(srv.URL+path, "application/x-www-form-urlencoded", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	for _, tags := range []string{"db,outage", "outage", "deploy"} {
+		body := "token=" + pushovertest.Token + "&user=" + pushovertest.UserKey + "&message=m&priority=2&retry=30&expire=60&tags=" + tags
+		if resp := post("/messages.json", body); resp.StatusCode != http.StatusOK {
+			t.Fatalf("send failed with %d", resp.StatusCode)
+		}
+	}
+	if resp := post("/receipts/cancel_by_tag/outage.json", "token="+pushovertest.Token); resp.StatusCode != http.StatusOK {
+		t.Fatalf("cancel_by_tag failed with %d", resp.StatusCode)
+	}
+
+	canceled := 0
+	for _, r := range srv.Receipts() {
+		if r.Canceled {
+			canceled++
+		}
+	}
+	if canceled != 2 {
+		t.Errorf("canceled %d receipts, want 2", canceled)
+	}
+}
+
+func TestFake_GroupMembers(t *testing.T) {
+	srv, client := newClient(t)
+	ctx := context.Background()
+	g := pushovertest.GroupKey
+
+	if _, err := client.AddGroupUser(ctx, g, pushovertest.UserKey, "iphone", "on call"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.AddGroupUser(ctx, g, pushovertest.UserKey, "iphone", "")
+	if got := apiErrors(t, err); !strings.Contains(got, "already a member") {
+		t.Errorf("errors = %q", got)
+	}
+	if _, err := client.DisableGroupUser(ctx, g, pushovertest.UserKey, "iphone"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RenameGroup(ctx, g, "On Call"); err != nil {
+		t.Fatal(err)
+	}
+
+	group, err := client.GetGroup(ctx, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.Name != "On Call" || len(group.Users) != 1 || !group.Users[0].Disabled || group.Users[0].Memo != "on call" {
+		t.Fatalf("unexpected group: %+v", group)
+	}
+
+	if _, err := client.RemoveGroupUser(ctx, g, pushovertest.UserKey, "iphone"); err != nil {
+		t.Fatal(err)
+	}
+	if _, members, _ := srv.Group(g); len(members) != 0 {
+		t.Errorf("expected an empty group, got %+v", members)
+	}
+	_, err = client.RemoveGroupUser(ctx, g, pushovertest.UserKey, "iphone")
+	if got := apiErrors(t, err); !strings.Contains(got, "not a member") {
+		t.Errorf("errors = %q", got)
+	}
+}
+
+func TestFake_ValidateUser(t *testing.T) {
+	_, client := newClient(t)
+	ctx := context.Background()
+
+	user, err := client.ValidateUser(ctx, &pushover.ValidateRequest{User: pushovertest.UserKey})
+	if err != nil || user.IsGroupKey() || len(user.Devices) != len(pushovertest.UserDevices) {
+		t.Fatalf("unexpected user %+v, err %v", user, err)
+	}
+	group, err := client.ValidateUser(ctx, &pushover.ValidateRequest{User: pushovertest.GroupKey})
+	if err != nil || !group.IsGroupKey() {
+		t.Fatalf("unexpected group %+v, err %v", group, err)
+	}
+	_, err = client.ValidateUser(ctx, &pushover.ValidateRequest{User: pushovertest.UserKey, Device: "toaster"})
+	if got := apiErrors(t, err); !strings.Contains(got, "device name is not valid") {
+		t.Errorf("errors = %q", got)
+	}
+}
+
+func TestFake_Glances(t *testing.T) {
+	srv, client := newClient(t)
+	ctx := context.Background()
+	title, count, percent := "Deploys", 3, 40
+	if _, err := client.UpdateGlance(ctx, &pushover.GlanceRequest{User: pushovertest.UserKey, Title: &title, Count: &count}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UpdateGlance(ctx, &pushover.GlanceRequest{User: pushovertest.UserKey, Percent: &percent}); err != nil {
+		t.Fatal(err)
+	}
+	g, err := srv.Glance(pushovertest.UserKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Title != title || g.Count == nil || *g.Count != count || g.Percent == nil || *g.Percent != percent {
+		t.Errorf("glance = %+v, want updates merged", g)
+	}
+	if _, err := srv.Glance(pushovertest.UserKey, "iphone"); !errors.Is(err, pushovertest.ErrNotFound) {
+		t.Errorf("per-device glance: err = %v, want ErrNotFound", err)
+	}
+
+	_, err = client.UpdateGlance(ctx, &pushover.GlanceRequest{User: pushovertest.UserKey, Device: "toaster", Title: &title})
+	if got := apiErrors(t, err); !strings.Contains(got, "device name is not valid") {
+		t.Errorf("errors = %q", got)
+	}
+}
+
+func TestFake_MigrateSubscription(t *testing.T) {
+	srv, client := newClient(t)
+	ctx := context.Background()
+	req := pushover.SubscriptionMigrateRequest{Subscription: pushovertest.SubscriptionCode, User: pushovertest.UserKey, DeviceName: "pixel"}
+	resp, err := client.MigrateSubscription(ctx, &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pushover.ValidateKey("subscribed_user_key", resp.SubscribedUserKey); err != nil || resp.SubscribedUserKey == pushovertest.UserKey {
+		t.Fatalf("unexpected subscribed key %q (%v)", resp.SubscribedUserKey, err)
+	}
+	again, err := client.MigrateSubscription(ctx, &req)
+	if err != nil || again.SubscribedUserKey != resp.SubscribedUserKey {
+		t.Errorf("second migration returned %+v, %v; want the same key", again, err)
+	}
+	if s, err := srv.Subscriber(resp.SubscribedUserKey); err != nil || s.User != pushovertest.UserKey || s.Device != "pixel" {
+		t.Errorf("subscriber = %+v, %v", s, err)
+	}
+
+	// The subscribed key receives messages, on the migrated device only.
+	if _, err := client.SendMessage(ctx, &pushover.MessageRequest{User: resp.SubscribedUserKey, Message: "hi"}); err != nil {
+		t.Errorf("sending to the subscribed key: %v", err)
+	}
+	_, err = client.SendMessage(ctx, &pushover.MessageRequest{User: resp.SubscribedUserKey, Message: "hi", Device: "iphone"})
+	if got := apiErrors(t, err); !strings.Contains(got, "not valid for user") {
+		t.Errorf("errors = %q", got)
+	}
+
+	_, err = client.MigrateSubscription(ctx, &pushover.SubscriptionMigrateRequest{Subscription: "unknown", User: pushovertest.UserKey})
+	if got := apiErrors(t, err); !strings.Contains(got, "subscription code is invalid") {
+		t.Errorf("errors = %q", got)
+	}
+}
+
+func TestFake_Teams(t *testing.T) {
+	srv, _ := newClient(t)
+	team := pushover.New(pushovertest.TeamToken, pushover.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	_, err := team.AddTeamUser(ctx, &pushover.TeamUserRequest{Email: "ada@example.com", Name: "Ada", Instant: true, Group: pushovertest.GroupKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := team.GetTeam(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Name != "Fake Team" || len(resp.Users) != 1 || resp.Users[0].Email != "ada@example.com" {
+		t.Fatalf("unexpected team: %+v", resp)
+	}
+	key := resp.Users[0].User
+	if _, members, _ := srv.Group(pushovertest.GroupKey); len(members) != 1 || members[0].User != key {
+		t.Errorf("group members = %+v, want the new member %s", members, key)
+	}
+	if _, members, _ := srv.Team(pushovertest.TeamToken); len(members) != 1 || !members[0].Instant {
+		t.Errorf("members = %+v, want one instant member", members)
+	}
+
+	_, err = team.AddTeamUser(ctx, &pushover.TeamUserRequest{Email: "ADA@example.com"})
+	if got := apiErrors(t, err); !strings.Contains(got, "already a member") {
+		t.Errorf("errors = %q", got)
+	}
+	if _, err := team.RemoveTeamUser(ctx, "Ada@Example.com"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = team.RemoveTeamUser(ctx, "ada@example.com")
+	if got := apiErrors(t, err); !strings.Contains(got, "not a member") {
+		t.Errorf("errors = %q", got)
+	}
+
+	// Application tokens cannot manage teams, nor team tokens send messages.
+	app := pushover.New(pushovertest.Token, pushover.WithBaseURL(srv.URL))
+	if _, err := app.GetTeam(ctx); !strings.Contains(apiErrors(t, err), "team token is invalid") {
+		t.Errorf("GetTeam with an application token: %v", err)
+	}
+	_, err = team.SendMessage(ctx, &pushover.MessageRequest{User: pushovertest.UserKey, Message: "hi"})
+	if got := apiErrors(t, err); !strings.Contains(got, "application token is invalid") {
+		t.Errorf("errors = %q", got)
+	}
+}
+
+func TestFake_Licenses(t *testing.T) {
+	srv, client := newClient(t)
+	srv.SetCredits(2)
+	ctx := context.Background()
+
+	if _, err := client.AssignLicense(ctx, &pushover.LicenseAssignRequest{User: pushovertest.UserKey, OS: pushover.LicenseDesktop}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.AssignLicense(ctx, &pushover.LicenseAssignRequest{Email: "ada@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if credits, err := client.GetLicenseCredits(ctx); err != nil || credits.Credits != 0 {
+		t.Errorf("credits = %+v, %v; want 0", credits, err)
+	}
+	if got := srv.Licenses(); len(got) != 2 || got[0].OS != pushover.LicenseDesktop || got[1].Email != "ada@example.com" {
+		t.Errorf("licenses = %+v", got)
+	}
+
+	// The assigned platform is reported by user validation.
+	v, err := client.ValidateUser(ctx, &pushover.ValidateRequest{User: pushovertest.UserKey})
+	if err != nil || !slices.Contains(v.Licenses, pushover.LicenseDesktop) {
+		t.Errorf("licenses = %v, %v; want Desktop included", v, err)
+	}
+
+	_, err = client.AssignLicense(ctx, &pushover.LicenseAssignRequest{User: pushovertest.OtherUserKey})
+	if got := apiErrors(t, err); !strings.Contains(got, "no license credits") {
+		t.Errorf("errors = %q", got)
+	}
+}
+
+func TestFake_Limits(t *testing.T) {
+	srv, client := newClient(t)
+	srv.SetLimit(100, 1)
+	ctx := context.Background()
+	req := pushover.MessageRequest{User: pushovertest.UserKey, Message: "hi"}
+	if _, err := client.SendMessage(ctx, &req); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.SendMessage(ctx, &req)
+	var apiErr *pushover.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != http.StatusTooManyRequests {
+		t.Fatalf("expected a 429 APIError, got %v", err)
+	}
+}
+
+func TestFake_FailNext(t *testing.T) {
+	srv, _ := newClient(t)
+	srv.FailNext("sounds.json", http.StatusServiceUnavailable)
+	client := pushover.New(pushovertest.Token,
+		pushover.WithBaseURL(srv.URL),
+		pushover.WithRetry(pushover.RetryPolicy{MaxAttempts: 2, MinBackoff: 1, MaxBackoff: 1}),
+	)
+	sounds, err := client.GetSounds(context.Background())
+	if err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if len(sounds) != len(pushovertest.Sounds) {
+		t.Errorf("got %d sounds, want %d", len(sounds), len(pushovertest.Sounds))
+	}
+
+	srv.FailNext("groups/{group}/add_user.json", http.StatusBadRequest, "group is locked")
+	_, err = client.AddGroupUser(context.Background(), pushovertest.GroupKey, pushovertest.UserKey, "", "")
+	if got := apiErrors(t, err); got != "group is locked" {
+		t.Errorf("errors = %q", got)
+	}
+}
@@ -0,0 +1,660 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushovertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+)
+
+// Limits enforced by the Pushover API.
+const (
+	maxMessageLength  = 1024
+	maxTitleLength    = 250
+	maxURLLength      = 512
+	maxURLTitleLength = 100
+	maxMemoLength     = 200
+	maxGlanceLength   = 100
+	maxRecipients     = 50
+	minRetry          = 30
+	maxExpire         = 10800
+)
+
+// Sounds are the sounds served by sounds.json and accepted in messages.
+var Sounds = map[string]string{
+	"pushover":     "Pushover (default)",
+	"bike":         "Bike",
+	"bugle":        "Bugle",
+	"cashregister": "Cash Register",
+	"classical":    "Classical",
+	"cosmic":       "Cosmic",
+	"falling":      "Falling",
+	"gamelan":      "Gamelan",
+	"incoming":     "Incoming",
+	"intermission": "Intermission",
+	"magic":        "Magic",
+	"mechanical":   "Mechanical",
+	"pianobar":     "Piano Bar",
+	"siren":        "Siren",
+	"spacealarm":   "Space Alarm",
+	"tugboat":      "Tug Boat",
+	"alien":        "Alien Alarm (long)",
+	"climb":        "Climb (long)",
+	"persistent":   "Persistent (long)",
+	"echo":         "Pushover Echo (long)",
+	"updown":       "Up Down (long)",
+	"vibrate":      "Vibrate Only",
+	"none":         "None (silent)",
+}
+
+var deviceName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,25}$`)
+
+// apiError is a rejected request. field, when set, is reported as
+// "<field>": "invalid", as the real API does.
+type apiError struct {
+	status int
+	field  string
+	errors []string
+}
+
+func invalid(field string, msg string) *apiError {
+	return &apiError{status: http.StatusBadRequest, field: field, errors: []string{msg}}
+}
+
+// handler serves one endpoint. It runs with f.mu held and returns the fields
+// of a successful response.
+type handler func(r *http.Request) (map[string]interface{}, *apiError)
+
+func (f *Fake) routes() {
+	f.mux = http.NewServeMux()
+	f.mux.HandleFunc("POST /1/messages.json", f.serve("messages.json", f.sendMessage))
+	f.mux.HandleFunc("GET /1/receipts/{file}", func(w http.ResponseWriter, r *http.Request) {
+		f.serve("receipts/{receipt}.json", f.getReceipt)(w, r)
+	})
+	f.mux.HandleFunc("POST /1/receipts/{id}/{action}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "cancel_by_tag" {
+			f.serve("receipts/cancel_by_tag/{tag}.json", f.cancelByTag)(w, r)
+			return
+		}
+		f.serve("receipts/{receipt}/"+r.PathValue("action"), f.cancelReceipt)(w, r)
+	})
+	f.mux.HandleFunc("POST /1/glances.json", f.serve("glances.json", f.updateGlance))
+	f.mux.HandleFunc("POST /1/subscriptions/migrate.json", f.serve("subscriptions/migrate.json", f.migrateSubscription))
+	f.mux.HandleFunc("GET /1/teams.json", f.serve("teams.json", f.getTeam))
+	f.mux.HandleFunc("POST /1/teams/add_user.json", f.serve("teams/add_user.json", f.addTeamUser))
+	f.mux.HandleFunc("POST /1/teams/remove_user.json", f.serve("teams/remove_user.json", f.removeTeamUser))
+	f.mux.HandleFunc("GET /1/licenses.json", f.serve("licenses.json", f.getLicenses))
+	f.mux.HandleFunc("POST /1/licenses/assign.json", f.serve("licenses/assign.json", f.assignLicense))
+	f.mux.HandleFunc("GET /1/sounds.json", f.serve("sounds.json", f.getSounds))
+	f.mux.HandleFunc("POST /1/users/validate.json", f.serve("users/validate.json", f.validateUser))
+	f.mux.HandleFunc("GET /1/groups/{file}", func(w http.ResponseWriter, r *http.Request) {
+		f.serve("groups/{group}.json", f.getGroup)(w, r)
+	})
+	f.mux.HandleFunc("POST /1/groups/{group}/{action}", func(w http.ResponseWriter, r *http.Request) {
+		f.serve("groups/{group}/"+r.PathValue("action"), f.updateGroup)(w, r)
+	})
+	f.mux.HandleFunc("GET /1/apps/limits.json", f.serve("apps/limits.json", f.getLimits))
+}
+
+// serve wraps h with failure injection, token checks and response encoding.
+func (f *Fake) serve(endpoint string, h handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		request := newRequestID()
+		if queued := f.failures[endpoint]; len(queued) > 0 {
+			f.failures[endpoint] = queued[1:]
+			if queued[0].status >= 500 {
+				http.Error(w, http.StatusText(queued[0].status), queued[0].status)
+				return
+			}
+			writeError(w, request, &apiError{status: queued[0].status, errors: queued[0].errors})
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			writeError(w, request, invalid("", "request could not be parsed"))
+			return
+		}
+		// The Teams API takes a team token in place of an application token.
+		if token := r.Form.Get("token"); strings.HasPrefix(endpoint, "teams") {
+			if f.teams[token] == nil {
+				writeError(w, request, invalid("token", "team token is invalid"))
+				return
+			}
+		} else if !f.apps[token] {
+			writeError(w, request, invalid("token", "application token is invalid"))
+			return
+		}
+		if endpoint == "messages.json" {
+			f.writeLimitHeaders(w)
+		}
+
+		out, apiErr := h(r)
+		if apiErr != nil {
+			writeError(w, request, apiErr)
+			return
+		}
+		if out == nil {
+			out = map[string]interface{}{}
+		}
+		out["status"] = 1
+		if _, ok := out["request"]; !ok {
+			out["request"] = request
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+func writeError(w http.ResponseWriter, request string, e *apiError) {
+	out := map[string]interface{}{"status": 0, "request": request, "errors": e.errors}
+	if e.field != "" {
+		out[e.field] = "invalid"
+	}
+	writeJSON(w, e.status, out)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (f *Fake) writeLimitHeaders(w http.ResponseWriter) {
+	w.Header().Set("X-Limit-App-Limit", strconv.Itoa(f.limit))
+	w.Header().Set("X-Limit-App-Remaining", strconv.Itoa(f.remaining))
+	w.Header().Set("X-Limit-App-Reset", strconv.FormatInt(f.limitReset().Unix(), 10))
+}
+
+// limitReset returns the start of next month, when the message limit resets.
+func (f *Fake) limitReset() time.Time {
+	now := f.now().UTC()
+	return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (f *Fake) sendMessage(r *http.Request) (map[string]interface{}, *apiError) {
+	form := r.Form
+	if f.remaining <= 0 {
+		return nil, &apiError{status: http.StatusTooManyRequests, errors: []string{"application is over its monthly message limit"}}
+	}
+
+	users := splitList(form.Get("user"))
+	if len(users) == 0 {
+		return nil, invalid("user", "user identifier is invalid")
+	}
+	if len(users) > maxRecipients {
+		return nil, invalid("user", "too many users, maximum is 50")
+	}
+	for _, u := range users {
+		if !f.isRecipient(u) {
+			return nil, invalid("user", "user identifier is not a valid user, group, or subscribed user key")
+		}
+	}
+
+	msg := Message{
+		Users:     users,
+		Message:   form.Get("message"),
+		Title:     form.Get("title"),
+		URL:       form.Get("url"),
+		URLTitle:  form.Get("url_title"),
+		Sound:     form.Get("sound"),
+		Device:    form.Get("device"),
+		Callback:  form.Get("callback"),
+		HTML:      form.Get("html") == "1",
+		Monospace: form.Get("monospace") == "1",
+		Tags:      splitList(form.Get("tags")),
+		SentAt:    f.now(),
+	}
+	switch n := utf8.RuneCountInString(msg.Message); {
+	case strings.TrimSpace(msg.Message) == "":
+		return nil, invalid("message", "message cannot be blank")
+	case n > maxMessageLength:
+		return nil, invalid("message", "message is too long, maximum is 1024 characters")
+	}
+	if utf8.RuneCountInString(msg.Title) > maxTitleLength {
+		return nil, invalid("title", "title is too long, maximum is 250 characters")
+	}
+	if utf8.RuneCountInString(msg.URL) > maxURLLength {
+		return nil, invalid("url", "url is too long, maximum is 512 characters")
+	}
+	if utf8.RuneCountInString(msg.URLTitle) > maxURLTitleLength {
+		return nil, invalid("url_title", "url_title is too long, maximum is 100 characters")
+	}
+	if msg.HTML && msg.Monospace {
+		return nil, invalid("monospace", "html and monospace cannot both be set")
+	}
+	if msg.Sound != "" {
+		if _, ok := Sounds[msg.Sound]; !ok {
+			return nil, invalid("sound", "sound is invalid")
+		}
+	}
+	for _, d := range splitList(msg.Device) {
+		if !deviceName.MatchString(d) {
+			return nil, invalid("device", "device name is invalid")
+		}
+		if len(users) == 1 && f.users[users[0]] != nil && !slices.Contains(f.users[users[0]], d) {
+			return nil, invalid("device", "device name is not valid for user")
+		}
+	}
+
+	var err *apiError
+	if msg.Priority, err = intParam(form.Get("priority"), "priority"); err != nil {
+		return nil, err
+	}
+	if msg.Priority < -2 || msg.Priority > 2 {
+		return nil, invalid("priority", "priority is invalid")
+	}
+	if msg.TTL, err = intParam(form.Get("ttl"), "ttl"); err != nil || msg.TTL < 0 {
+		return nil, invalid("ttl", "ttl is invalid")
+	}
+	if ts := form.Get("timestamp"); ts != "" {
+		v, perr := strconv.ParseInt(ts, 10, 64)
+		if perr != nil || v < 0 {
+			return nil, invalid("timestamp", "timestamp is invalid")
+		}
+		msg.Timestamp = v
+	}
+
+	request := newRequestID()
+	msg.Request = request
+	out := map[string]interface{}{"request": request}
+	if msg.Priority == 2 {
+		if msg.Retry, err = intParam(form.Get("retry"), "retry"); err != nil || form.Get("retry") == "" {
+			return nil, invalid("retry", "retry is invalid")
+		}
+		if msg.Retry < minRetry {
+			return nil, invalid("retry", "retry must be at least 30 seconds")
+		}
+		if msg.Expire, err = intParam(form.Get("expire"), "expire"); err != nil || form.Get("expire") == "" || msg.Expire < 1 {
+			return nil, invalid("expire", "expire is invalid")
+		}
+		if msg.Expire > maxExpire {
+			return nil, invalid("expire", "expire must be at most 10800 seconds")
+		}
+		msg.Receipt = newKey()
+		f.receipts[msg.Receipt] = &Receipt{
+			Receipt:   msg.Receipt,
+			Request:   request,
+			Users:     users,
+			Tags:      msg.Tags,
+			Callback:  msg.Callback,
+			ExpiresAt: msg.SentAt.Add(time.Duration(msg.Expire) * time.Second),
+		}
+		out["receipt"] = msg.Receipt
+	}
+
+	f.messages = append(f.messages, msg)
+	f.remaining--
+	return out, nil
+}
+
+func (f *Fake) getReceipt(r *http.Request) (map[string]interface{}, *apiError) {
+	id, ok := strings.CutSuffix(r.PathValue("file"), ".json")
+	rec, found := f.receipts[id]
+	if !ok || !found {
+		return nil, &apiError{status: http.StatusNotFound, field: "receipt", errors: []string{"receipt not found; may be invalid or expired"}}
+	}
+	// Notifications are redelivered until the receipt is acknowledged,
+	// expires or is canceled.
+	now := f.now()
+	lastDelivered := now
+	if rec.Acknowledged {
+		lastDelivered = rec.AcknowledgedAt
+	} else if rec.Expired(now) {
+		lastDelivered = rec.ExpiresAt
+	}
+	return map[string]interface{}{
+		"acknowledged":           boolInt(rec.Acknowledged),
+		"acknowledged_at":        unix(rec.AcknowledgedAt),
+		"acknowledged_by":        rec.AcknowledgedBy,
+		"acknowledged_by_device": rec.AcknowledgedByDevice,
+		"last_delivered_at":      unix(lastDelivered),
+		"expired":                boolInt(rec.Expired(now) || rec.Canceled),
+		"expires_at":             rec.ExpiresAt.Unix(),
+		"called_back":            boolInt(!rec.CalledBackAt.IsZero()),
+		"called_back_at":         unix(rec.CalledBackAt),
+	}, nil
+}
+
+func (f *Fake) cancelReceipt(r *http.Request) (map[string]interface{}, *apiError) {
+	rec, ok := f.receipts[r.PathValue("id")]
+	if r.PathValue("action") != "cancel.json" || !ok {
+		return nil, &apiError{status: http.StatusNotFound, field: "receipt", errors: []string{"receipt not found; may be invalid or expired"}}
+	}
+	rec.Canceled = true
+	return nil, nil
+}
+
+func (f *Fake) cancelByTag(r *http.Request) (map[string]interface{}, *apiError) {
+	tag, ok := strings.CutSuffix(r.PathValue("action"), ".json")
+	if !ok || tag == "" {
+		return nil, invalid("tag", "tag is invalid")
+	}
+	canceled := 0
+	for _, rec := range f.receipts {
+		if !rec.Canceled && slices.Contains(rec.Tags, tag) {
+			rec.Canceled = true
+			canceled++
+		}
+	}
+	return map[string]interface{}{"canceled": canceled}, nil
+}
+
+func (f *Fake) getSounds(*http.Request) (map[string]interface{}, *apiError) {
+	return map[string]interface{}{"sounds": Sounds}, nil
+}
+
+func (f *Fake) updateGlance(r *http.Request) (map[string]interface{}, *apiError) {
+	form := r.Form
+	user, device := form.Get("user"), form.Get("device")
+	if !f.isRecipient(user) {
+		return nil, invalid("user", "user identifier is not a valid user, group, or subscribed user key")
+	}
+	if devices, ok := f.users[user]; ok && device != "" && !slices.Contains(devices, device) {
+		return nil, invalid("device", "device name is not valid for user")
+	}
+
+	g, ok := f.glances[glanceKey(user, device)]
+	if !ok {
+		g = &Glance{User: user, Device: device}
+	}
+	updated := false
+	for field, dst := range map[string]*string{"title": &g.Title, "text": &g.Text, "subtext": &g.Subtext} {
+		if !form.Has(field) {
+			continue
+		}
+		v := form.Get(field)
+		if utf8.RuneCountInString(v) > maxGlanceLength {
+			return nil, invalid(field, field+" is too long, maximum is 100 characters")
+		}
+		*dst, updated = v, true
+	}
+	if form.Has("count") {
+		n, err := strconv.Atoi(form.Get("count"))
+		if err != nil {
+			return nil, invalid("count", "count must be an integer")
+		}
+		g.Count, updated = &n, true
+	}
+	if form.Has("percent") {
+		n, err := strconv.Atoi(form.Get("percent"))
+		if err != nil || n < 0 || n > 100 {
+			return nil, invalid("percent", "percent must be an integer from 0 to 100")
+		}
+		g.Percent, updated = &n, true
+	}
+	if !updated {
+		return nil, invalid("glance", "at least one of title, text, subtext, count or percent must be supplied")
+	}
+	g.UpdatedAt = f.now()
+	f.glances[glanceKey(user, device)] = g
+	return nil, nil
+}
+
+func (f *Fake) migrateSubscription(r *http.Request) (map[string]interface{}, *apiError) {
+	form := r.Form
+	code, user, device, sound := form.Get("subscription"), form.Get("user"), form.Get("device_name"), form.Get("sound")
+	subscribers, ok := f.subscriptions[code]
+	if !ok {
+		return nil, invalid("subscription", "subscription code is invalid")
+	}
+	devices, ok := f.users[user]
+	if !ok {
+		return nil, invalid("user", "user key is invalid")
+	}
+	if device != "" && !slices.Contains(devices, device) {
+		return nil, invalid("device_name", "device name is not valid for user")
+	}
+	if _, ok := Sounds[sound]; sound != "" && !ok {
+		return nil, invalid("sound", "sound is invalid")
+	}
+
+	// Migrating the same user again returns the key it was given before.
+	s, ok := subscribers[user]
+	if !ok {
+		s = &Subscriber{Key: "u" + newKey()[1:], Subscription: code, User: user}
+		subscribers[user] = s
+	}
+	s.Device, s.Sound = device, sound
+	if device != "" {
+		devices = []string{device}
+	}
+	f.users[s.Key] = slices.Clone(devices)
+	return map[string]interface{}{"subscribed_user_key": s.Key}, nil
+}
+
+func (f *Fake) getTeam(r *http.Request) (map[string]interface{}, *apiError) {
+	t := f.teams[r.Form.Get("token")]
+	users := make([]pushover.TeamMember, 0, len(t.members))
+	for _, m := range t.members {
+		users = append(users, m.TeamMember)
+	}
+	return map[string]interface{}{"name": t.name, "users": users}, nil
+}
+
+func (f *Fake) addTeamUser(r *http.Request) (map[string]interface{}, *apiError) {
+	t := f.teams[r.Form.Get("token")]
+	email, groupKey := r.Form.Get("email"), r.Form.Get("group")
+	if local, domain, ok := strings.Cut(email, "@"); !ok || local == "" || domain == "" {
+		return nil, invalid("email", "email address is invalid")
+	}
+	if t.member(email) >= 0 {
+		return nil, invalid("email", "user is already a member of this team")
+	}
+	var g *group
+	if groupKey != "" {
+		var err *apiError
+		if g, err = f.group(groupKey); err != nil {
+			return nil, err
+		}
+	}
+
+	// Every member gets a new account; it has no devices until the user
+	// logs in to the Pushover app.
+	key := "u" + newKey()[1:]
+	f.users[key] = nil
+	t.members = append(t.members, TeamMember{
+		TeamMember: pushover.TeamMember{Name: r.Form.Get("name"), Email: email, User: key},
+		Instant:    r.Form.Get("instant") == "1",
+	})
+	if g != nil {
+		g.members = append(g.members, pushover.GroupMember{User: key, Memo: r.Form.Get("name")})
+	}
+	return nil, nil
+}
+
+func (f *Fake) removeTeamUser(r *http.Request) (map[string]interface{}, *apiError) {
+	t := f.teams[r.Form.Get("token")]
+	i := t.member(r.Form.Get("email"))
+	if i < 0 {
+		return nil, invalid("email", "user is not a member of this team")
+	}
+	t.members = slices.Delete(t.members, i, i+1)
+	return nil, nil
+}
+
+func (f *Fake) getLicenses(*http.Request) (map[string]interface{}, *apiError) {
+	return map[string]interface{}{"credits": f.credits}, nil
+}
+
+func (f *Fake) assignLicense(r *http.Request) (map[string]interface{}, *apiError) {
+	user, email, platform := r.Form.Get("user"), r.Form.Get("email"), r.Form.Get("os")
+	switch {
+	case (user == "") == (email == ""):
+		return nil, invalid("user", "either user or email must be supplied")
+	case user != "":
+		if _, ok := f.users[user]; !ok {
+			return nil, invalid("user", "user key is invalid")
+		}
+	default:
+		if local, domain, ok := strings.Cut(email, "@"); !ok || local == "" || domain == "" {
+			return nil, invalid("email", "email address is invalid")
+		}
+	}
+	if platform != "" && !slices.Contains(pushover.LicensePlatforms, platform) {
+		return nil, invalid("os", "os must be Android, iOS or Desktop")
+	}
+	if f.credits <= 0 {
+		return nil, invalid("", "no license credits remain")
+	}
+	f.credits--
+	f.licenses = append(f.licenses, License{User: user, Email: email, OS: platform, AssignedAt: f.now()})
+	return nil, nil
+}
+
+func (f *Fake) validateUser(r *http.Request) (map[string]interface{}, *apiError) {
+	user, device := r.Form.Get("user"), r.Form.Get("device")
+	if _, ok := f.groups[user]; ok {
+		return map[string]interface{}{"group": 1, "devices": []string{}, "licenses": []string{}}, nil
+	}
+	devices, ok := f.users[user]
+	if !ok {
+		return nil, invalid("user", "user key is invalid")
+	}
+	if device != "" && !slices.Contains(devices, device) {
+		return nil, invalid("device", "device name is not valid for user")
+	}
+	// Every user is licensed for Android and iOS, plus any platform
+	// assigned to them through the Licensing API.
+	licenses := []string{"Android", "iOS"}
+	for _, l := range f.licenses {
+		if l.User == user && l.OS != "" && !slices.Contains(licenses, l.OS) {
+			licenses = append(licenses, l.OS)
+		}
+	}
+	return map[string]interface{}{"group": 0, "devices": devices, "licenses": licenses}, nil
+}
+
+func (f *Fake) getGroup(r *http.Request) (map[string]interface{}, *apiError) {
+	key, _ := strings.CutSuffix(r.PathValue("file"), ".json")
+	g, err := f.group(key)
+	if err != nil {
+		return nil, err
+	}
+	members := g.members
+	if members == nil {
+		members = []pushover.GroupMember{}
+	}
+	return map[string]interface{}{"name": g.name, "users": members}, nil
+}
+
+func (f *Fake) updateGroup(r *http.Request) (map[string]interface{}, *apiError) {
+	g, err := f.group(r.PathValue("group"))
+	if err != nil {
+		return nil, err
+	}
+	action := r.PathValue("action")
+	if action == "rename.json" {
+		name := r.Form.Get("name")
+		if strings.TrimSpace(name) == "" {
+			return nil, invalid("name", "name cannot be blank")
+		}
+		g.name = name
+		return nil, nil
+	}
+
+	user, device := r.Form.Get("user"), r.Form.Get("device")
+	devices, ok := f.users[user]
+	if !ok {
+		return nil, invalid("user", "user key is invalid")
+	}
+	i := g.member(user, device)
+	switch action {
+	case "add_user.json":
+		if device != "" && !slices.Contains(devices, device) {
+			return nil, invalid("device", "device name is not valid for user")
+		}
+		if utf8.RuneCountInString(r.Form.Get("memo")) > maxMemoLength {
+			return nil, invalid("memo", "memo is too long, maximum is 200 characters")
+		}
+		if i >= 0 {
+			return nil, invalid("user", "user is already a member of this group")
+		}
+		g.members = append(g.members, pushover.GroupMember{User: user, Device: device, Memo: r.Form.Get("memo")})
+	case "delete_user.json", "disable_user.json", "enable_user.json":
+		if i < 0 {
+			return nil, invalid("user", "user is not a member of this group")
+		}
+		switch action {
+		case "delete_user.json":
+			g.members = slices.Delete(g.members, i, i+1)
+		case "disable_user.json":
+			g.members[i].Disabled = true
+		default:
+			g.members[i].Disabled = false
+		}
+	default:
+		return nil, &apiError{status: http.StatusNotFound, errors: []string{"not found"}}
+	}
+	return nil, nil
+}
+
+func (f *Fake) group(key string) (*group, *apiError) {
+	g, ok := f.groups[key]
+	if !ok {
+		return nil, invalid("group", "group not found or you are not authorized to edit it")
+	}
+	return g, nil
+}
+
+func (f *Fake) getLimits(*http.Request) (map[string]interface{}, *apiError) {
+	return map[string]interface{}{
+		"limit":     f.limit,
+		"remaining": f.remaining,
+		"reset":     f.limitReset().Unix(),
+	}, nil
+}
+
+// isRecipient reports whether key is a registered user or group.
+func (f *Fake) isRecipient(key string) bool {
+	_, isUser := f.users[key]
+	_, isGroup := f.groups[key]
+	return isUser || isGroup
+}
+
+// intParam parses an optional integer form value.
+func intParam(v, field string) (int, *apiError) {
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, invalid(field, field+" is invalid")
+	}
+	return n, nil
+}
+
+// splitList splits a comma-separated form value, dropping empty entries.
+func splitList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,284 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushovertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RecordEnv is the environment variable that, when set to a non-empty value,
+// makes every Recorder forward requests to the real API and write them to
+// its fixture file instead of replaying it.
+const RecordEnv = "PUSHOVER_RECORD"
+
+// recordedHeaders are the response headers kept in fixtures.
+var recordedHeaders = []string{"Content-Type", "X-Limit-App-Limit", "X-Limit-App-Remaining", "X-Limit-App-Reset"}
+
+// secretKey matches application tokens and user, group and receipt keys.
+var secretKey = regexp.MustCompile(`\b[A-Za-z0-9]{30}\b`)
+
+// emailAddress matches the email addresses sent to the Teams and Licensing APIs.
+var emailAddress = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// Fixture is the content of a fixture file: the HTTP exchanges of one test,
+// in the order they were made.
+type Fixture struct {
+	// Note describes where the fixture came from, such as that it was
+	// written by hand rather than recorded. Recording leaves it empty.
+	Note         string        `json:"note,omitempty"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a redacted request.
+type RecordedRequest struct {
+	Method string     `json:"method"`
+	Path   string     `json:"path"`
+	Form   url.Values `json:"form,omitempty"`
+}
+
+// RecordedResponse is a redacted response. Body holds a JSON body as is;
+// BodyText holds any other body.
+type RecordedResponse struct {
+	Status   int               `json:"status"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Body     json.RawMessage   `json:"body,omitempty"`
+	BodyText string            `json:"body_text,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that replays the exchanges in a fixture
+// file, or, when RecordEnv is set, records real exchanges into it.
+//
+// Fixtures never contain secrets: every token or key is replaced by a
+// placeholder of the same shape, such as "uREDACTED000000000000000000001",
+// and every email address by one such as "redacted1@example.com", used
+// consistently within the file, so that replayed receipts can be passed back
+// to the client. Requests are replayed in order and matched on their
+// method and path with keys ignored, so any well-formed key may be used when
+// replaying.
+type Recorder struct {
+	path      string
+	recording bool
+	next      http.RoundTripper
+
+	mu      sync.Mutex
+	fixture Fixture
+	pos     int
+	aliases map[string]string
+}
+
+// NewRecorder returns a Recorder for the fixture file at path. When
+// recording, requests are sent through next, or http.DefaultTransport if next
+// is nil, and Save writes the file. When replaying, the file must exist.
+func NewRecorder(path string, next http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		recording: os.Getenv(RecordEnv) != "",
+		next:      next,
+		aliases:   map[string]string{},
+	}
+	if r.next == nil {
+		r.next = http.DefaultTransport
+	}
+	if r.recording {
+		return r, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture (set %s=1 to record it): %w", RecordEnv, err)
+	}
+	if err := json.Unmarshal(raw, &r.fixture); err != nil {
+		return nil, fmt.Errorf("decoding fixture %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Recording reports whether the recorder sends requests to the real API.
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// RoundTrip records or replays one exchange.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	form, err := requestForm(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.recording {
+		return r.replay(req)
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	rec := Interaction{
+		Request: RecordedRequest{Method: req.Method, Path: r.redact(req.URL.Path)},
+		Response: RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: map[string]string{},
+		},
+	}
+	// Walk the fields in order so that placeholders are numbered the same
+	// way every time an exchange is recorded.
+	for _, k := range slices.Sorted(maps.Keys(form)) {
+		for _, v := range form[k] {
+			if rec.Request.Form == nil {
+				rec.Request.Form = url.Values{}
+			}
+			rec.Request.Form.Add(k, r.redact(v))
+		}
+	}
+	for _, h := range recordedHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			rec.Response.Headers[h] = v
+		}
+	}
+	redacted := r.redact(string(body))
+	if json.Valid([]byte(redacted)) {
+		rec.Response.Body = json.RawMessage(redacted)
+	} else {
+		rec.Response.BodyText = redacted
+	}
+	r.fixture.Interactions = append(r.fixture.Interactions, rec)
+	return resp, nil
+}
+
+// replay answers req with the next recorded interaction.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	if r.pos >= len(r.fixture.Interactions) {
+		return nil, fmt.Errorf("fixture %s has no interaction for %s %s", r.path, req.Method, endpoint(req.URL.Path))
+	}
+	rec := r.fixture.Interactions[r.pos]
+	if rec.Request.Method != req.Method || endpoint(rec.Request.Path) != endpoint(req.URL.Path) {
+		return nil, fmt.Errorf("fixture %s: interaction %d is %s %s, got %s %s",
+			r.path, r.pos, rec.Request.Method, endpoint(rec.Request.Path), req.Method, endpoint(req.URL.Path))
+	}
+	r.pos++
+
+	header := http.Header{}
+	for k, v := range rec.Response.Headers {
+		header.Set(k, v)
+	}
+	body := []byte(rec.Response.BodyText)
+	if len(rec.Response.Body) > 0 {
+		body = rec.Response.Body
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Response.Status, http.StatusText(rec.Response.Status)),
+		StatusCode:    rec.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Save writes the fixture file when recording. When replaying, it reports
+// an error if some recorded interactions were never requested.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.recording {
+		if unused := len(r.fixture.Interactions) - r.pos; unused > 0 {
+			return fmt.Errorf("fixture %s: %d recorded interaction(s) were not replayed", r.path, unused)
+		}
+		return nil
+	}
+	raw, err := json.MarshalIndent(r.fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(raw, '\n'), 0o644)
+}
+
+// redact replaces every key and email address in s with its placeholder.
+func (r *Recorder) redact(s string) string {
+	s = secretKey.ReplaceAllStringFunc(s, func(key string) string {
+		return r.alias(key, func(n string) string {
+			return key[:1] + "REDACTED" + strings.Repeat("0", 30-9-len(n)) + n
+		})
+	})
+	return emailAddress.ReplaceAllStringFunc(s, func(email string) string {
+		return r.alias(email, func(n string) string {
+			return "redacted" + n + "@example.com"
+		})
+	})
+}
+
+// alias returns the placeholder for secret, creating it with placeholder the
+// first time secret is seen.
+func (r *Recorder) alias(secret string, placeholder func(n string) string) string {
+	alias, ok := r.aliases[secret]
+	if !ok {
+		alias = placeholder(fmt.Sprint(len(r.aliases) + 1))
+		r.aliases[secret] = alias
+	}
+	return alias
+}
+
+// endpoint returns path with keys replaced by a placeholder.
+func endpoint(path string) string {
+	return secretKey.ReplaceAllString(path, "{key}")
+}
+
+// requestForm returns the query and form parameters of req without
+// consuming its body.
+func requestForm(req *http.Request) (url.Values, error) {
+	form := req.URL.Query()
+	if req.Body == nil || req.GetBody == nil {
+		return form, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	posted, err := url.ParseQuery(string(raw))
+	if err != nil {
+		return nil, errors.New("request body is not form-encoded")
+	}
+	for k, vs := range posted {
+		form[k] = append(form[k], vs...)
+	}
+	return form, nil
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushovertest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
+)
+
+// sendAndCheck sends an emergency message and reads its receipt.
+func sendAndCheck(t *testing.T, client *pushover.Client, user string) *pushover.ReceiptResponse {
+	t.Helper()
+	ctx := context.Background()
+	sent, err := client.SendMessage(ctx, &pushover.MessageRequest{User: user, Message: "wake up", Priority: 2, Retry: 30, Expire: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := client.GetReceipt(ctx, sent.Receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return receipt
+}
+
+func TestRecorder_RecordThenReplay(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "emergency.json")
+	srv, _ := newClient(t)
+
+	t.Setenv(pushovertest.RecordEnv, "1")
+	rec, err := pushovertest.NewRecorder(fixture, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := pushover.New(pushovertest.Token, pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(&http.Client{Transport: rec}))
+	recorded := sendAndCheck(t, client, pushovertest.UserKey)
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{pushovertest.Token, pushovertest.UserKey} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("fixture contains %q:\n%s", secret, raw)
+		}
+	}
+
+	// Replay without a server, using a different but well-formed key.
+	t.Setenv(pushovertest.RecordEnv, "")
+	rec, err = pushovertest.NewRecorder(fixture, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+	client = pushover.New(pushovertest.Token, pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(&http.Client{Transport: rec}))
+	replayed := sendAndCheck(t, client, pushovertest.OtherUserKey)
+	if replayed.ExpiresAt != recorded.ExpiresAt || replayed.Request != recorded.Request {
+		t.Errorf("replayed %+v, recorded %+v", replayed, recorded)
+	}
+	if err := rec.Save(); err != nil {
+		t.Errorf("unexpected error after replaying every interaction: %v", err)
+	}
+}
+
+func TestRecorder_RedactsEmailAddresses(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "team.json")
+	srv, _ := newClient(t)
+
+	t.Setenv(pushovertest.RecordEnv, "1")
+	rec, err := pushovertest.NewRecorder(fixture, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	team := pushover.New(pushovertest.TeamToken, pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(&http.Client{Transport: rec}))
+	if _, err := team.AddTeamUser(context.Background(), &pushover.TeamUserRequest{Email: "ada@example.org", Name: "Ada"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := team.GetTeam(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "ada@example.org") {
+		t.Errorf("fixture contains the email address:\n%s", raw)
+	}
+	if strings.Count(string(raw), "redacted1@example.com") != 2 {
+		t.Errorf("expected the request and the team listing to share one placeholder:\n%s", raw)
+	}
+}
+
+func TestRecorder_ReplayMismatch(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "sounds.json")
+	err := os.WriteFile(fixture, []byte(`{"interactions":[{"request":{"method":"GET","path":"/1/sounds.json"},"response":{"status":200,"body":{"status":1,"sounds":{}}}}]}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := pushovertest.NewRecorder(fixture, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := pushover.New(pushovertest.Token, pushover.WithHTTPClient(&http.Client{Transport: rec}))
+
+	if _, err := client.CancelReceipt(context.Background(), "rLqVuqTRh62UzxtmqiaLzQmVcPSiCy"); err == nil || !strings.Contains(err.Error(), "interaction 0 is GET /1/sounds.json") {
+		t.Errorf("expected a mismatch error, got %v", err)
+	}
+	if err := rec.Save(); err == nil {
+		t.Error("expected Save to report the unused interaction")
+	}
+}
+
+func TestRecorder_MissingFixture(t *testing.T) {
+	_, err := pushovertest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), nil)
+	if !errors.Is(err, os.ErrNotExist) || !strings.Contains(err.Error(), pushovertest.RecordEnv) {
+		t.Errorf("expected a not-exist error mentioning %s, got %v", pushovertest.RecordEnv, err)
+	}
+}
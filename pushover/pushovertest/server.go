@@ -0,0 +1,29 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushovertest
+
+import "net/http/httptest"
+
+// Server is a Fake listening on a local port.
+type Server struct {
+	*Fake
+
+	// URL is the API base URL, including the /1 version prefix, to pass to
+	// pushover.WithBaseURL or the provider's base_url.
+	URL string
+
+	srv *httptest.Server
+}
+
+// NewServer starts a Server backed by a new Fake. Close it when done.
+func NewServer() *Server {
+	f := NewFake()
+	srv := httptest.NewServer(f)
+	return &Server{Fake: f, URL: srv.URL + "/1", srv: srv}
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
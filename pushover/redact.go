@@ -0,0 +1,159 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces tokens and keys in error messages and log output.
+const Redacted = "REDACTED"
+
+// keyPattern matches strings shaped like Pushover tokens and keys.
+var keyPattern = regexp.MustCompile(`\b[A-Za-z0-9]{30}\b`)
+
+// redactedError is an error whose message has had secrets masked. It unwraps
+// to a redacted copy of the original error, so callers can still use
+// errors.Is and errors.As without reaching the unmasked values.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
+
+// redactError masks the client token, the given secrets and anything shaped
+// like a Pushover key in err and every error it wraps.
+func (c *Client) redactError(err error, secrets ...string) error {
+	if err == nil {
+		return nil
+	}
+	redacted, _ := redactChain(err, append(secrets, c.token))
+	return redacted
+}
+
+// redactChain returns err with secrets masked throughout its chain, and
+// whether anything was masked. *APIError and *url.Error values are copied
+// with masked fields so that they keep their types; other errors are wrapped
+// in a redactedError when they or the errors they wrap change.
+func redactChain(err error, secrets []string) (error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	switch e := err.(type) {
+	case *APIError:
+		redacted := &APIError{HTTPStatus: e.HTTPStatus, Errors: make([]string, len(e.Errors)), InvalidFields: e.InvalidFields}
+		for i, msg := range e.Errors {
+			redacted.Errors[i] = redactString(msg, secrets...)
+		}
+		return redacted, true
+	case *url.Error:
+		inner, _ := redactChain(e.Err, secrets)
+		return &url.Error{Op: e.Op, URL: redactURL(e.URL), Err: inner}, true
+	}
+
+	inner := errors.Unwrap(err)
+	changed := false
+	if inner != nil {
+		inner, changed = redactChain(inner, secrets)
+	}
+	msg := redactString(err.Error(), secrets...)
+	if msg == err.Error() && !changed {
+		return err, false
+	}
+	return &redactedError{msg: msg, err: inner}, true
+}
+
+// redactURLError masks the URL of a *url.Error returned by the HTTP client,
+// which carries the token in the query string of GET requests.
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactURL(urlErr.URL)
+	}
+	return err
+}
+
+// redactURL masks every query parameter value and key-shaped path segment of raw.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return keyPattern.ReplaceAllString(raw, Redacted)
+	}
+	q := u.Query()
+	for k := range q {
+		q.Set(k, Redacted)
+	}
+	u.RawQuery = q.Encode()
+	u.Path = keyPattern.ReplaceAllString(u.Path, Redacted)
+	u.RawPath = ""
+	return u.String()
+}
+
+// minSecretLength is the length below which a value passed as a secret is
+// not masked. Real tokens and keys are 30 characters; masking short values,
+// such as a mistyped "invalid" user key, would only mangle API messages.
+const minSecretLength = 8
+
+// redactString masks secrets and key-shaped substrings in s.
+func redactString(s string, secrets ...string) string {
+	for _, secret := range secrets {
+		if len(secret) >= minSecretLength {
+			s = strings.ReplaceAll(s, secret, Redacted)
+		}
+	}
+	return keyPattern.ReplaceAllString(s, Redacted)
+}
+
+// mask returns Redacted for a non-empty secret.
+func mask(s string) string {
+	if s == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// String formats the request with Token and User masked.
+func (r MessageRequest) String() string {
+	type plain MessageRequest
+	p := plain(r)
+	p.Token, p.User = mask(p.Token), mask(p.User)
+	return fmt.Sprintf("MessageRequest%+v", p)
+}
+
+// LogValue implements slog.LogValuer with Token and User masked.
+func (r MessageRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("token", mask(r.Token)),
+		slog.String("user", mask(r.User)),
+		slog.String("title", r.Title),
+		slog.Int("priority", r.Priority),
+		slog.String("sound", r.Sound),
+		slog.String("device", r.Device),
+	)
+}
+
+// String formats the request with Token and User masked.
+func (r ValidateRequest) String() string {
+	type plain ValidateRequest
+	p := plain(r)
+	p.Token, p.User = mask(p.Token), mask(p.User)
+	return fmt.Sprintf("ValidateRequest%+v", p)
+}
+
+// LogValue implements slog.LogValuer with Token and User masked.
+func (r ValidateRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("token", mask(r.Token)),
+		slog.String("user", mask(r.User)),
+		slog.String("device", r.Device),
+	)
+}
@@ -0,0 +1,320 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
+)
+
+// The tests in this file replay API exchanges from testdata/fixtures, and
+// the other tests in this package answer with the response bodies those
+// fixtures hold. Fixtures whose "note" field says they are synthetic were
+// written by hand from the API documentation. To record them from the live
+// API instead, run:
+//
+//	PUSHOVER_RECORD=1 PUSHOVER_API_TOKEN=... PUSHOVER_USER_KEY=... go test ./pushover -run Replay
+//
+// Fixtures for groups, subscriptions, teams and licenses are recorded only
+// when PUSHOVER_GROUP_KEY, PUSHOVER_SUBSCRIPTION_CODE, PUSHOVER_TEAM_TOKEN
+// and PUSHOVER_LICENSE_EMAIL are set; the tests say what each one changes.
+
+// Keys that are well formed but belong to no application or user.
+const (
+	invalidToken   = "aAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	unknownUserKey = "uAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+)
+
+// fixturePath returns the path of the named fixture file.
+func fixturePath(fixture string) string {
+	return filepath.Join("testdata", "fixtures", fixture+".json")
+}
+
+// replayEnv returns the environment variable name when recording, skipping
+// the test if it is unset, and replay otherwise. Call it before
+// replayHTTPClient, so that a skipped test does not overwrite its fixture.
+func replayEnv(t *testing.T, name, replay string) string {
+	t.Helper()
+	if os.Getenv(pushovertest.RecordEnv) == "" {
+		return replay
+	}
+	value := os.Getenv(name)
+	if value == "" {
+		t.Skipf("recording requires %s", name)
+	}
+	return value
+}
+
+// replayHTTPClient returns an HTTP client whose requests are answered from
+// the named fixture, or recorded into it.
+func replayHTTPClient(t *testing.T, fixture string) *http.Client {
+	t.Helper()
+	rec, err := pushovertest.NewRecorder(fixturePath(fixture), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := rec.Save(); err != nil {
+			t.Error(err)
+		}
+	})
+	return &http.Client{Transport: rec}
+}
+
+// replayClient returns a client whose requests are answered from the named
+// fixture, and the user key to send to.
+func replayClient(t *testing.T, fixture string) (*pushover.Client, string) {
+	t.Helper()
+	token := replayEnv(t, "PUSHOVER_API_TOKEN", secretToken)
+	user := replayEnv(t, "PUSHOVER_USER_KEY", testUserKey)
+	return pushover.New(token, pushover.WithHTTPClient(replayHTTPClient(t, fixture))), user
+}
+
+// fixtureBody returns the response body of interaction i of the named
+// fixture. Tests that check what the client sends answer with it, so that
+// they parse what the API returns rather than hand-written JSON.
+func fixtureBody(t *testing.T, fixture string, i int) []byte {
+	t.Helper()
+	raw, err := os.ReadFile(fixturePath(fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f pushovertest.Fixture
+	if err := json.Unmarshal(raw, &f); err != nil {
+		t.Fatalf("decoding fixture %s: %v", fixture, err)
+	}
+	if i >= len(f.Interactions) {
+		t.Fatalf("fixture %s has %d interactions, want at least %d", fixture, len(f.Interactions), i+1)
+	}
+	resp := f.Interactions[i].Response
+	if len(resp.Body) > 0 {
+		return resp.Body
+	}
+	return []byte(resp.BodyText)
+}
+
+// fixtureResponse decodes the response body of interaction i of the named
+// fixture, for tests to compare what the client parsed against.
+func fixtureResponse[T any](t *testing.T, fixture string, i int) *T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(fixtureBody(t, fixture, i), &v); err != nil {
+		t.Fatalf("decoding fixture %s response %d: %v", fixture, i, err)
+	}
+	return &v
+}
+
+func TestReplay_SendMessage(t *testing.T) {
+	client, user := replayClient(t, "send_message")
+	resp, err := client.SendMessage(context.Background(), &pushover.MessageRequest{User: user, Message: "fixture test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != 1 || resp.Request == "" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestReplay_FieldKeyedError(t *testing.T) {
+	client, _ := replayClient(t, "send_invalid_user")
+	_, err := client.SendMessage(context.Background(), &pushover.MessageRequest{User: unknownUserKey, Message: "fixture test"})
+	var apiErr *pushover.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.HTTPStatus != http.StatusBadRequest || len(apiErr.Errors) != 1 || !apiErr.FieldInvalid("user") {
+		t.Errorf("unexpected error: %+v", apiErr)
+	}
+}
+
+func TestReplay_InvalidToken(t *testing.T) {
+	client := pushover.New(invalidToken, pushover.WithHTTPClient(replayHTTPClient(t, "invalid_token")))
+	_, err := client.GetSounds(context.Background())
+	var apiErr *pushover.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.HTTPStatus != http.StatusBadRequest || !apiErr.FieldInvalid("token") {
+		t.Errorf("unexpected error: %+v", apiErr)
+	}
+}
+
+func TestReplay_CustomSounds(t *testing.T) {
+	client, _ := replayClient(t, "sounds_custom")
+	sounds, err := client.GetSounds(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Custom sounds uploaded to an application are listed by their file name.
+	i := slices.IndexFunc(sounds, func(s pushover.Sound) bool { return s.Key == "deploy_chime" })
+	if i < 0 || sounds[i].Name != "deploy_chime" {
+		t.Errorf("custom sound missing from %+v", sounds)
+	}
+}
+
+func TestReplay_ValidateUserLicenses(t *testing.T) {
+	client, user := replayClient(t, "validate_user_licenses")
+	ctx := context.Background()
+
+	resp, err := client.ValidateUser(ctx, &pushover.ValidateRequest{User: user})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.IsGroupKey() || len(resp.Devices) == 0 || len(resp.Licenses) == 0 {
+		t.Errorf("unexpected validation: %+v", resp)
+	}
+
+	_, err = client.ValidateUser(ctx, &pushover.ValidateRequest{User: user, Device: "toaster"})
+	var apiErr *pushover.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != http.StatusBadRequest || !apiErr.FieldInvalid("device") {
+		t.Fatalf("expected a 400 *APIError flagging the device, got %v", err)
+	}
+
+	_, err = client.ValidateUser(ctx, &pushover.ValidateRequest{User: unknownUserKey})
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != http.StatusBadRequest || !apiErr.FieldInvalid("user") {
+		t.Fatalf("expected a 400 *APIError flagging the user, got %v", err)
+	}
+}
+
+func TestReplay_EmergencyReceipt(t *testing.T) {
+	client, user := replayClient(t, "emergency_receipt")
+	ctx := context.Background()
+
+	sent, err := client.SendMessage(ctx, &pushover.MessageRequest{User: user, Message: "fixture test", Priority: 2, Retry: 30, Expire: 600})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pushover.ValidateKey("receipt", sent.Receipt); err != nil {
+		t.Fatalf("replayed receipt is not usable: %v", err)
+	}
+	receipt, err := client.GetReceipt(ctx, sent.Receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.ExpiresAt == 0 || receipt.LastDeliveredAt == 0 {
+		t.Errorf("unexpected receipt: %+v", receipt)
+	}
+	if _, err := client.CancelReceipt(ctx, sent.Receipt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestReplay_GroupMembership adds the user to a group, disables, lists,
+// renames (to its current name), enables and removes them, then validates
+// the group key. When recording, the user must not already be a member of
+// PUSHOVER_GROUP_KEY.
+func TestReplay_GroupMembership(t *testing.T) {
+	group := replayEnv(t, "PUSHOVER_GROUP_KEY", testGroupKey)
+	client, user := replayClient(t, "group_membership")
+	ctx := context.Background()
+
+	if _, err := client.AddGroupUser(ctx, group, user, "", "fixture test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.DisableGroupUser(ctx, group, user, ""); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.GetGroup(ctx, group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.IndexFunc(resp.Users, func(m pushover.GroupMember) bool { return m.Memo == "fixture test" })
+	if resp.Name == "" || i < 0 || !resp.Users[i].Disabled {
+		t.Errorf("unexpected group: %+v", resp)
+	}
+	if _, err := client.RenameGroup(ctx, group, resp.Name); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.EnableGroupUser(ctx, group, user, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RemoveGroupUser(ctx, group, user, ""); err != nil {
+		t.Fatal(err)
+	}
+	validated, err := client.ValidateUser(ctx, &pushover.ValidateRequest{User: group})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !validated.IsGroupKey() {
+		t.Errorf("expected a group key: %+v", validated)
+	}
+}
+
+func TestReplay_Glance(t *testing.T) {
+	client, user := replayClient(t, "glance_update")
+	title, count := "Deploys", 12
+	resp, err := client.UpdateGlance(context.Background(), &pushover.GlanceRequest{User: user, Title: &title, Count: &count})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != 1 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+// TestReplay_SubscriptionMigrate migrates the user to a subscription. When
+// recording, it subscribes PUSHOVER_USER_KEY to PUSHOVER_SUBSCRIPTION_CODE.
+func TestReplay_SubscriptionMigrate(t *testing.T) {
+	code := replayEnv(t, "PUSHOVER_SUBSCRIPTION_CODE", "fixture-subscription")
+	client, user := replayClient(t, "subscription_migrate")
+	resp, err := client.MigrateSubscription(context.Background(), &pushover.SubscriptionMigrateRequest{Subscription: code, User: user})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pushover.ValidateKey("user", resp.SubscribedUserKey); err != nil {
+		t.Errorf("unexpected subscribed user key: %v", err)
+	}
+}
+
+// TestReplay_Team adds a member to a team, lists the team and removes the
+// member again. When recording, ada@example.com is invited to the team of
+// PUSHOVER_TEAM_TOKEN and removed.
+func TestReplay_Team(t *testing.T) {
+	token := replayEnv(t, "PUSHOVER_TEAM_TOKEN", secretToken)
+	client := pushover.New(token, pushover.WithHTTPClient(replayHTTPClient(t, "team")))
+	ctx := context.Background()
+	const email = "ada@example.com"
+
+	if _, err := client.AddTeamUser(ctx, &pushover.TeamUserRequest{Email: email, Name: "Ada Lovelace"}); err != nil {
+		t.Fatal(err)
+	}
+	team, err := client.GetTeam(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team.Name == "" || !slices.ContainsFunc(team.Users, func(m pushover.TeamMember) bool { return m.Name == "Ada Lovelace" }) {
+		t.Errorf("unexpected team: %+v", team)
+	}
+	if _, err := client.RemoveTeamUser(ctx, email); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestReplay_Licenses reads the license credits and assigns a Desktop
+// license. When recording, it spends one credit on PUSHOVER_LICENSE_EMAIL.
+func TestReplay_Licenses(t *testing.T) {
+	email := replayEnv(t, "PUSHOVER_LICENSE_EMAIL", "ada@example.com")
+	client, _ := replayClient(t, "licenses")
+	ctx := context.Background()
+
+	credits, err := client.GetLicenseCredits(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credits.Credits < 1 {
+		t.Fatalf("no credits to assign: %+v", credits)
+	}
+	if _, err := client.AssignLicense(ctx, &pushover.LicenseAssignRequest{Email: email, OS: pushover.LicenseDesktop}); err != nil {
+		t.Fatal(err)
+	}
+}
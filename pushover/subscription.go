@@ -0,0 +1,62 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// SubscriptionMigrateRequest converts an existing user key into a key
+// subscribed to one of the application's subscriptions.
+type SubscriptionMigrateRequest struct {
+	Token string
+	// Subscription is the subscription code shown on the subscription's
+	// page in the Pushover dashboard.
+	Subscription string
+	User         string
+	// DeviceName limits the subscription to one of the user's devices.
+	DeviceName string
+	// Sound is the user's preferred sound for notifications from the
+	// subscription.
+	Sound string
+}
+
+// SubscriptionMigrateResponse is the response from migrating a user to a
+// subscription.
+type SubscriptionMigrateResponse struct {
+	APIResponse
+	SubscribedUserKey string `json:"subscribed_user_key"`
+}
+
+// MigrateSubscription subscribes an existing user key to a subscription and
+// returns the subscription-specific user key, which is used in place of the
+// user's key when sending messages.
+func (c *Client) MigrateSubscription(ctx context.Context, req *SubscriptionMigrateRequest) (*SubscriptionMigrateResponse, error) {
+	if req.Token == "" {
+		req.Token = c.token
+	}
+	if strings.TrimSpace(req.Subscription) == "" {
+		return nil, &ValidationError{Field: "subscription", Reason: "must not be empty"}
+	}
+	if err := ValidateKey("user", req.User); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("token", req.Token)
+	params.Set("subscription", req.Subscription)
+	params.Set("user", req.User)
+	if req.DeviceName != "" {
+		params.Set("device_name", req.DeviceName)
+	}
+	if req.Sound != "" {
+		params.Set("sound", req.Sound)
+	}
+	var resp SubscriptionMigrateResponse
+	if err := c.doPost(ctx, "/subscriptions/migrate.json", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
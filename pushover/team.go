@@ -0,0 +1,108 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// The Teams API of Pushover for Teams authenticates with the team's API
+// token, shown in the team's settings, rather than an application token.
+// Call these methods on a Client created with that token.
+
+// TeamMember is a member of a Pushover for Teams team.
+type TeamMember struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// User is the member's user key.
+	User string `json:"user"`
+}
+
+// TeamResponse is the response from reading a team.
+type TeamResponse struct {
+	APIResponse
+	Name  string       `json:"name"`
+	Users []TeamMember `json:"users"`
+}
+
+// TeamUserRequest adds a user to a team.
+type TeamUserRequest struct {
+	Email string
+	Name  string
+	// Password sets the new account's password. When empty, the user is
+	// emailed an invitation to choose one.
+	Password string
+	// Instant creates the account without waiting for the user to accept
+	// the invitation.
+	Instant bool
+	// Group adds the new member to this delivery group.
+	Group string
+}
+
+// GetTeam retrieves the team and its members.
+func (c *Client) GetTeam(ctx context.Context) (*TeamResponse, error) {
+	path := fmt.Sprintf("/teams.json?token=%s", url.QueryEscape(c.token))
+	var resp TeamResponse
+	if err := c.doGet(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddTeamUser adds a user to the team by email address, creating a Pushover
+// account for them if needed.
+func (c *Client) AddTeamUser(ctx context.Context, req *TeamUserRequest) (*APIResponse, error) {
+	if err := validateEmail(req.Email); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("token", c.token)
+	params.Set("email", req.Email)
+	if req.Name != "" {
+		params.Set("name", req.Name)
+	}
+	if req.Password != "" {
+		params.Set("password", req.Password)
+	}
+	if req.Instant {
+		params.Set("instant", "1")
+	}
+	if req.Group != "" {
+		if err := ValidateKey("group", req.Group); err != nil {
+			return nil, err
+		}
+		params.Set("group", req.Group)
+	}
+	var resp APIResponse
+	if err := c.doPost(ctx, "/teams/add_user.json", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RemoveTeamUser removes the user with the given email address from the team.
+func (c *Client) RemoveTeamUser(ctx context.Context, email string) (*APIResponse, error) {
+	if err := validateEmail(email); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("token", c.token)
+	params.Set("email", email)
+	var resp APIResponse
+	if err := c.doPost(ctx, "/teams/remove_user.json", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func validateEmail(email string) error {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" || domain == "" || strings.ContainsAny(email, " \t\r\n") {
+		return &ValidationError{Field: "email", Reason: "must be an email address"}
+	}
+	return nil
+}
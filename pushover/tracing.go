@@ -0,0 +1,135 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans created by this package.
+const tracerName = "github.com/Josh-Archer/terraform-provider-pushover/pushover"
+
+// Span attribute keys.
+const (
+	attrEndpoint     = attribute.Key("pushover.endpoint")
+	attrRequestID    = attribute.Key("pushover.request_id")
+	attrRetryAttempt = attribute.Key("pushover.retry_attempt")
+	attrHTTPMethod   = attribute.Key("http.request.method")
+	attrHTTPStatus   = attribute.Key("http.response.status_code")
+	attrErrorType    = attribute.Key("error.type")
+)
+
+// WithTracerProvider enables OpenTelemetry tracing. Every API call creates a
+// client span, as a child of the span in the caller's context, recording the
+// endpoint, HTTP status, Pushover request ID, retry attempt and error type.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *Client) {
+		if tp != nil {
+			c.tracer = tp.Tracer(tracerName)
+		}
+	}
+}
+
+// endpointName returns path with its query removed and keys replaced by a
+// placeholder, so that span names have low cardinality and carry no secrets.
+func endpointName(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	return keyPattern.ReplaceAllString(path, "{key}")
+}
+
+// startSpan starts the span for one API call, or returns ctx unchanged and a
+// nil span when tracing is disabled.
+func (c *Client) startSpan(ctx context.Context, method, endpoint string) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, nil
+	}
+	ctx, span := c.tracer.Start(ctx, "pushover "+method+" "+endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrEndpoint.String(endpoint), attrHTTPMethod.String(method)),
+	)
+	return withAttemptCounter(ctx), span
+}
+
+// endSpan records the outcome of an API call on span and ends it.
+func (c *Client) endSpan(ctx context.Context, span trace.Span, status int, requestID string, err error) {
+	if span == nil {
+		return
+	}
+	attempts := attemptsFrom(ctx)
+	if attempts > 0 {
+		attempts--
+	}
+	span.SetAttributes(attrRetryAttempt.Int64(attempts))
+	if status != 0 {
+		span.SetAttributes(attrHTTPStatus.Int(status))
+	}
+	if requestID != "" {
+		span.SetAttributes(attrRequestID.String(requestID))
+	}
+	if err != nil {
+		span.SetAttributes(attrErrorType.String(errorType(err)))
+		span.SetStatus(codes.Error, redactString(err.Error(), c.token))
+	}
+	span.End()
+}
+
+// errorType classifies err for the error.type span attribute.
+func errorType(err error) string {
+	var apiErr *APIError
+	var netErr net.Error
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &apiErr):
+		return "api_error"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return "decode_error"
+	default:
+		return "transport_error"
+	}
+}
+
+type attemptCounterKey struct{}
+
+// withAttemptCounter returns a context in which countingTransport records
+// every HTTP attempt, including retries.
+func withAttemptCounter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, attemptCounterKey{}, new(atomic.Int64))
+}
+
+// attemptsFrom returns the number of HTTP attempts recorded in ctx.
+func attemptsFrom(ctx context.Context) int64 {
+	if n, ok := ctx.Value(attemptCounterKey{}).(*atomic.Int64); ok {
+		return n.Load()
+	}
+	return 0
+}
+
+// countingTransport counts the HTTP attempts made for each API call. It sits
+// directly beneath the Retry middleware so that every attempt is counted,
+// including those that custom middleware fails before reaching the network.
+type countingTransport struct {
+	next http.RoundTripper
+}
+
+func (t countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if n, ok := req.Context().Value(attemptCounterKey{}).(*atomic.Int64); ok {
+		n.Add(1)
+	}
+	return t.next.RoundTrip(req)
+}
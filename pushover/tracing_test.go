@@ -0,0 +1,116 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+)
+
+func newTracedClient(t *testing.T, handler http.HandlerFunc) (*pushover.Client, *tracetest.InMemoryExporter, *sdktrace.TracerProvider) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	return pushover.New(secretToken, pushover.WithBaseURL(srv.URL), pushover.WithHTTPClient(srv.Client()), pushover.WithTracerProvider(tp)), exporter, tp
+}
+
+func spanAttrs(s tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value, len(s.Attributes))
+	for _, kv := range s.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracing_SpanPerCall(t *testing.T) {
+	body := fixtureBody(t, "group_membership", 2)
+	client, exporter, tp := newTracedClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(body)
+	})
+
+	parentCtx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	if _, err := client.GetGroup(parentCtx, testGroupKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "pushover GET /groups/{key}.json" {
+		t.Errorf("unexpected span name %q", span.Name)
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("expected the API span to be a child of the caller's span")
+	}
+	attrs := spanAttrs(span)
+	if attrs["pushover.endpoint"].AsString() != "/groups/{key}.json" {
+		t.Errorf("unexpected endpoint %q", attrs["pushover.endpoint"].AsString())
+	}
+	if attrs["http.response.status_code"].AsInt64() != http.StatusOK {
+		t.Errorf("unexpected status %v", attrs["http.response.status_code"])
+	}
+	if want := fixtureResponse[pushover.GroupResponse](t, "group_membership", 2).Request; attrs["pushover.request_id"].AsString() != want {
+		t.Errorf("unexpected request id %q", attrs["pushover.request_id"].AsString())
+	}
+	if attrs["pushover.retry_attempt"].AsInt64() != 0 {
+		t.Errorf("unexpected retry attempt %v", attrs["pushover.retry_attempt"])
+	}
+	if _, ok := attrs["error.type"]; ok {
+		t.Error("did not expect error.type on a successful call")
+	}
+}
+
+func TestTracing_APIError(t *testing.T) {
+	var calls atomic.Int32
+	body := fixtureBody(t, "invalid_token", 0)
+	client, exporter, _ := newTracedClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write(body)
+	})
+
+	if _, err := client.SendMessage(context.Background(), &pushover.MessageRequest{User: testUserKey, Message: "hi"}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	attrs := spanAttrs(spans[0])
+	if attrs["error.type"].AsString() != "api_error" {
+		t.Errorf("unexpected error type %q", attrs["error.type"].AsString())
+	}
+	if calls.Load() != 1 || attrs["pushover.retry_attempt"].AsInt64() != 0 {
+		t.Errorf("expected a single attempt, got %d calls and retry attempt %v", calls.Load(), attrs["pushover.retry_attempt"])
+	}
+	if attrs["http.response.status_code"].AsInt64() != http.StatusBadRequest {
+		t.Errorf("unexpected status %v", attrs["http.response.status_code"])
+	}
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("expected error status, got %v", spans[0].Status.Code)
+	}
+	if strings.Contains(spans[0].Status.Description, secretToken) {
+		t.Error("span status leaks the token")
+	}
+}
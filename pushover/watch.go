@@ -0,0 +1,262 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// MinReceiptPollInterval is the shortest interval at which Pushover allows a
+// receipt to be polled. Shorter intervals passed to a ReceiptWatcher are
+// raised to it.
+const MinReceiptPollInterval = 5 * time.Second
+
+// DefaultWatchRate is the number of receipt polls per second a
+// ReceiptWatcher sends across all of its receipts unless WithWatchLimiter is
+// given.
+const DefaultWatchRate = 2
+
+// minReceiptPollInterval is MinReceiptPollInterval, lowered by tests.
+var minReceiptPollInterval = MinReceiptPollInterval
+
+// ErrReceiptExpired is returned by WaitForAcknowledgement when the
+// notification stopped retrying without being acknowledged.
+var ErrReceiptExpired = errors.New("receipt expired without acknowledgement")
+
+// ReceiptStatus is a state reached by an emergency notification.
+type ReceiptStatus string
+
+// Receipt statuses reported by a ReceiptWatcher. Acknowledged and Expired are
+// terminal, unless the watch was started with AwaitCallback, in which case
+// CalledBack is terminal in place of Acknowledged.
+const (
+	ReceiptDelivered    ReceiptStatus = "delivered"
+	ReceiptAcknowledged ReceiptStatus = "acknowledged"
+	ReceiptCalledBack   ReceiptStatus = "called_back"
+	ReceiptExpired      ReceiptStatus = "expired"
+)
+
+// ReceiptEvent is a status transition of a watched receipt. When polling
+// fails, Err is set, Status is empty and the receipt is no longer watched.
+type ReceiptEvent struct {
+	Receipt string
+	Status  ReceiptStatus
+	// At is when the transition happened, as reported by Pushover.
+	At       time.Time
+	Response *ReceiptResponse
+	Err      error
+}
+
+// ReceiptWatcher polls emergency receipts and reports their status
+// transitions. All receipts watched by one ReceiptWatcher share its rate
+// limit, so a single watcher can track many receipts without exceeding the
+// API's limits. Create it with NewReceiptWatcher.
+type ReceiptWatcher struct {
+	api      API
+	interval time.Duration
+	limiter  Limiter
+}
+
+// WatchOption configures a ReceiptWatcher.
+type WatchOption func(*ReceiptWatcher)
+
+// WithPollInterval sets how often each receipt is polled. Intervals below
+// MinReceiptPollInterval are raised to it.
+func WithPollInterval(d time.Duration) WatchOption {
+	return func(w *ReceiptWatcher) {
+		w.interval = d
+	}
+}
+
+// WithWatchLimiter makes every poll wait for l, in place of the watcher's
+// own limit of DefaultWatchRate polls per second.
+func WithWatchLimiter(l Limiter) WatchOption {
+	return func(w *ReceiptWatcher) {
+		w.limiter = l
+	}
+}
+
+// NewReceiptWatcher returns a ReceiptWatcher that polls receipts through api.
+func NewReceiptWatcher(api API, opts ...WatchOption) *ReceiptWatcher {
+	w := &ReceiptWatcher{
+		api:      api,
+		interval: minReceiptPollInterval,
+		limiter:  rate.NewLimiter(DefaultWatchRate, 1),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.interval < minReceiptPollInterval {
+		w.interval = minReceiptPollInterval
+	}
+	return w
+}
+
+// ReceiptOption configures the watch of a single receipt.
+type ReceiptOption func(*receiptWatch)
+
+type receiptWatch struct {
+	awaitCallback bool
+}
+
+// AwaitCallback keeps watching an acknowledged receipt until Pushover reports
+// that it called the callback URL, or the receipt expires. Use it for
+// messages sent with a Callback, since the API does not say whether a
+// receipt has one.
+func AwaitCallback() ReceiptOption {
+	return func(rw *receiptWatch) {
+		rw.awaitCallback = true
+	}
+}
+
+// Watch polls receipt until it is acknowledged or expires, polling fails, or
+// ctx is done, sending each status transition on the returned channel. The
+// channel is closed when watching stops.
+//
+// Without AwaitCallback, acknowledgement ends the watch, so
+// ReceiptCalledBack is only reported when Pushover has already called the
+// callback URL by the time the acknowledgement is seen.
+func (w *ReceiptWatcher) Watch(ctx context.Context, receipt string, opts ...ReceiptOption) (<-chan ReceiptEvent, error) {
+	if err := ValidateKey("receipt", receipt); err != nil {
+		return nil, err
+	}
+	var rw receiptWatch
+	for _, opt := range opts {
+		opt(&rw)
+	}
+	ch := make(chan ReceiptEvent, 4)
+	go w.watch(ctx, receipt, rw, ch)
+	return ch, nil
+}
+
+func (w *ReceiptWatcher) watch(ctx context.Context, receipt string, rw receiptWatch, ch chan<- ReceiptEvent) {
+	defer close(ch)
+	send := func(ev ReceiptEvent) bool {
+		select {
+		case ch <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	prev := &ReceiptResponse{}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return
+		}
+		if err := w.wait(ctx); err != nil {
+			if ctx.Err() == nil {
+				send(ReceiptEvent{Receipt: receipt, Err: err})
+			}
+			return
+		}
+		resp, err := w.api.GetReceipt(ctx, receipt)
+		if err != nil {
+			if ctx.Err() == nil {
+				send(ReceiptEvent{Receipt: receipt, Err: err})
+			}
+			return
+		}
+		for _, ev := range transitions(receipt, prev, resp) {
+			if !send(ev) {
+				return
+			}
+		}
+		done := resp.Acknowledged == 1 && (!rw.awaitCallback || resp.CalledBack == 1)
+		if done || resp.Expired == 1 {
+			return
+		}
+		prev = resp
+		timer.Reset(w.interval)
+	}
+}
+
+// wait waits for the watcher's limiter. rate.Limiter.Wait fails early when
+// the next token would arrive after ctx's deadline, so a *rate.Limiter is
+// reserved instead: a reservation that ctx would outlast is given back and
+// the wait lasts until ctx is done, so that the watch ends with ctx.
+func (w *ReceiptWatcher) wait(ctx context.Context) error {
+	rl, ok := w.limiter.(*rate.Limiter)
+	if !ok {
+		return w.limiter.Wait(ctx)
+	}
+	r := rl.Reserve()
+	if !r.OK() {
+		return rl.Wait(ctx)
+	}
+	delay := r.Delay()
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		r.Cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
+
+// transitions returns the statuses reached between two polls, in order.
+func transitions(receipt string, prev, cur *ReceiptResponse) []ReceiptEvent {
+	var events []ReceiptEvent
+	add := func(status ReceiptStatus, at int64) {
+		events = append(events, ReceiptEvent{Receipt: receipt, Status: status, At: time.Unix(at, 0), Response: cur})
+	}
+	if prev.LastDeliveredAt == 0 && cur.LastDeliveredAt != 0 {
+		add(ReceiptDelivered, cur.LastDeliveredAt)
+	}
+	if prev.Acknowledged == 0 && cur.Acknowledged == 1 {
+		add(ReceiptAcknowledged, cur.AcknowledgedAt)
+	}
+	if prev.CalledBack == 0 && cur.CalledBack == 1 {
+		add(ReceiptCalledBack, cur.CalledBackAt)
+	}
+	if prev.Expired == 0 && cur.Expired == 1 && cur.Acknowledged == 0 {
+		add(ReceiptExpired, cur.ExpiresAt)
+	}
+	return events
+}
+
+// WatchReceipt polls receipt every interval, but no faster than
+// MinReceiptPollInterval, and sends its status transitions on the returned
+// channel until it is acknowledged or expires. See ReceiptWatcher.Watch. To
+// watch many receipts under one rate limit, use a ReceiptWatcher.
+func (c *Client) WatchReceipt(ctx context.Context, receipt string, interval time.Duration, opts ...ReceiptOption) (<-chan ReceiptEvent, error) {
+	return NewReceiptWatcher(c, WithPollInterval(interval)).Watch(ctx, receipt, opts...)
+}
+
+// WaitForAcknowledgement blocks until receipt is acknowledged and returns
+// its final status. It returns ErrReceiptExpired, along with the final
+// status, if the notification expires first, and ctx's error if ctx is done.
+func (c *Client) WaitForAcknowledgement(ctx context.Context, receipt string, interval time.Duration) (*ReceiptResponse, error) {
+	events, err := c.WatchReceipt(ctx, receipt, interval)
+	if err != nil {
+		return nil, err
+	}
+	for ev := range events {
+		switch {
+		case ev.Err != nil:
+			return nil, ev.Err
+		case ev.Status == ReceiptAcknowledged:
+			return ev.Response, nil
+		case ev.Status == ReceiptExpired:
+			return ev.Response, ErrReceiptExpired
+		}
+	}
+	return nil, ctx.Err()
+}
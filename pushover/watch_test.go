@@ -0,0 +1,288 @@
+// Copyright (c) Josh Archer
+// SPDX-License-Identifier: MPL-2.0
+
+package pushover_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover"
+	"github.com/Josh-Archer/terraform-provider-pushover/pushover/pushovertest"
+)
+
+const testPollInterval = 10 * time.Millisecond
+
+// watchServer starts a fake API with the poll floor lowered for tests.
+func watchServer(t *testing.T, opts ...pushover.Option) (*pushovertest.Server, *pushover.Client) {
+	t.Helper()
+	t.Cleanup(pushover.SetMinReceiptPollInterval(testPollInterval))
+	srv := pushovertest.NewServer()
+	t.Cleanup(srv.Close)
+	opts = append([]pushover.Option{pushover.WithBaseURL(srv.URL)}, opts...)
+	return srv, pushover.New(pushovertest.Token, opts...)
+}
+
+// sendEmergency sends an emergency message and returns its receipt.
+func sendEmergency(t *testing.T, client *pushover.Client, callback string) string {
+	t.Helper()
+	resp, err := client.SendMessage(context.Background(), &pushover.MessageRequest{
+		User: pushovertest.UserKey, Message: "wake up", Priority: 2, Retry: 30, Expire: 3600, Callback: callback,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp.Receipt
+}
+
+// nextEvent returns the next event on events, failing after a second.
+func nextEvent(t *testing.T, events <-chan pushover.ReceiptEvent) (pushover.ReceiptEvent, bool) {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		return ev, ok
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a receipt event")
+		return pushover.ReceiptEvent{}, false
+	}
+}
+
+func TestWatchReceipt_Transitions(t *testing.T) {
+	srv, client := watchServer(t)
+	receipt := sendEmergency(t, client, "https://example.com/ack")
+
+	events, err := client.WatchReceipt(context.Background(), receipt, time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ev, _ := nextEvent(t, events); ev.Status != pushover.ReceiptDelivered || ev.Receipt != receipt {
+		t.Fatalf("first event = %+v, want delivered", ev)
+	}
+	if err := srv.Acknowledge(receipt, pushovertest.UserKey, "iphone"); err != nil {
+		t.Fatal(err)
+	}
+	ev, _ := nextEvent(t, events)
+	if ev.Status != pushover.ReceiptAcknowledged || ev.Response.AcknowledgedByDevice != "iphone" {
+		t.Fatalf("event = %+v, want acknowledged from iphone", ev)
+	}
+	if ev, _ := nextEvent(t, events); ev.Status != pushover.ReceiptCalledBack {
+		t.Fatalf("event = %+v, want called_back", ev)
+	}
+	if ev, ok := nextEvent(t, events); ok {
+		t.Fatalf("watch continued after acknowledgement: %+v", ev)
+	}
+}
+
+func TestWatchReceipt_AwaitCallback(t *testing.T) {
+	srv, client := watchServer(t)
+	receipt := sendEmergency(t, client, "https://example.com/ack")
+	if err := srv.HoldCallback(receipt); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := client.WatchReceipt(context.Background(), receipt, time.Millisecond, pushover.AwaitCallback())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ev, _ := nextEvent(t, events); ev.Status != pushover.ReceiptDelivered {
+		t.Fatalf("first event = %+v, want delivered", ev)
+	}
+	if err := srv.Acknowledge(receipt, pushovertest.UserKey, "iphone"); err != nil {
+		t.Fatal(err)
+	}
+	if ev, _ := nextEvent(t, events); ev.Status != pushover.ReceiptAcknowledged {
+		t.Fatalf("event = %+v, want acknowledged", ev)
+	}
+	select {
+	case ev, ok := <-events:
+		t.Fatalf("watch did not wait for the callback: event %+v, open %t", ev, ok)
+	case <-time.After(5 * testPollInterval):
+	}
+	if err := srv.CallBack(receipt); err != nil {
+		t.Fatal(err)
+	}
+	if ev, _ := nextEvent(t, events); ev.Status != pushover.ReceiptCalledBack {
+		t.Fatalf("event = %+v, want called_back", ev)
+	}
+	if ev, ok := nextEvent(t, events); ok {
+		t.Fatalf("watch continued after the callback: %+v", ev)
+	}
+}
+
+func TestWatchReceipt_PollError(t *testing.T) {
+	_, client := watchServer(t)
+	events, err := client.WatchReceipt(context.Background(), "rUnknownReceipt000000000000001", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ev, _ := nextEvent(t, events)
+	var apiErr *pushover.APIError
+	if !errors.As(ev.Err, &apiErr) {
+		t.Fatalf("event = %+v, want an API error", ev)
+	}
+	if _, ok := nextEvent(t, events); ok {
+		t.Fatal("watch continued after a poll error")
+	}
+}
+
+func TestWatchReceipt_InvalidReceipt(t *testing.T) {
+	_, client := watchServer(t)
+	if _, err := client.WatchReceipt(context.Background(), "short", 0); err == nil {
+		t.Fatal("expected a validation error")
+	}
+}
+
+func TestWatchReceipt_MinimumInterval(t *testing.T) {
+	t.Cleanup(pushover.SetMinReceiptPollInterval(50 * time.Millisecond))
+	srv := pushovertest.NewServer()
+	t.Cleanup(srv.Close)
+	var polls atomic.Int32
+	client := pushover.New(pushovertest.Token, pushover.WithBaseURL(srv.URL), pushover.WithMiddleware(
+		func(next http.RoundTripper) http.RoundTripper {
+			return pushover.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method == http.MethodGet {
+					polls.Add(1)
+				}
+				return next.RoundTrip(req)
+			})
+		}))
+	receipt := sendEmergency(t, client, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Millisecond)
+	defer cancel()
+	events, err := client.WatchReceipt(ctx, receipt, time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range events {
+	}
+	if n := polls.Load(); n > 3 {
+		t.Errorf("polled %d times in 120ms with a 50ms floor", n)
+	}
+}
+
+func TestReceiptWatcher_SharedLimiter(t *testing.T) {
+	srv, client := watchServer(t)
+	limiter := &recordingLimiter{}
+	watcher := pushover.NewReceiptWatcher(client, pushover.WithWatchLimiter(limiter))
+
+	var all []<-chan pushover.ReceiptEvent
+	for i := 0; i < 3; i++ {
+		receipt := sendEmergency(t, client, "")
+		events, err := watcher.Watch(context.Background(), receipt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := srv.Expire(receipt); err != nil {
+			t.Fatal(err)
+		}
+		all = append(all, events)
+	}
+	for _, events := range all {
+		var last pushover.ReceiptEvent
+		for ev := range events {
+			last = ev
+		}
+		if last.Status != pushover.ReceiptExpired {
+			t.Errorf("last event = %+v, want expired", last)
+		}
+	}
+	if n := limiter.waits.Load(); n < 3 {
+		t.Errorf("limiter waited %d times, want one per poll", n)
+	}
+}
+
+// failingLimiter refuses every wait with err.
+type failingLimiter struct{ err error }
+
+func (l failingLimiter) Wait(context.Context) error { return l.err }
+
+func TestReceiptWatcher_LimiterError(t *testing.T) {
+	_, client := watchServer(t)
+	limiterErr := errors.New("limiter closed")
+	watcher := pushover.NewReceiptWatcher(client, pushover.WithWatchLimiter(failingLimiter{limiterErr}))
+
+	events, err := watcher.Watch(context.Background(), sendEmergency(t, client, ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ev, _ := nextEvent(t, events); !errors.Is(ev.Err, limiterErr) {
+		t.Fatalf("event = %+v, want the limiter's error", ev)
+	}
+	if ev, ok := nextEvent(t, events); ok {
+		t.Fatalf("watch continued after a limiter error: %+v", ev)
+	}
+}
+
+func TestReceiptWatcher_LimiterPastDeadline(t *testing.T) {
+	_, client := watchServer(t)
+	limiter := rate.NewLimiter(rate.Every(time.Hour), 1)
+	watcher := pushover.NewReceiptWatcher(client, pushover.WithWatchLimiter(limiter))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*testPollInterval)
+	defer cancel()
+	events, err := watcher.Watch(ctx, sendEmergency(t, client, ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for ev := range events {
+		if ev.Err != nil {
+			t.Fatalf("watch reported the limiter's refusal: %v", ev.Err)
+		}
+	}
+	if ctx.Err() == nil {
+		t.Error("watch ended before its context")
+	}
+	// The reservation for the second poll is given back.
+	if tokens := limiter.Tokens(); tokens < -0.5 {
+		t.Errorf("limiter tokens = %.2f, want the second poll's token returned", tokens)
+	}
+}
+
+func TestWaitForAcknowledgement(t *testing.T) {
+	srv, client := watchServer(t)
+	receipt := sendEmergency(t, client, "")
+	go func() {
+		time.Sleep(3 * testPollInterval)
+		_ = srv.Acknowledge(receipt, pushovertest.UserKey, "pixel")
+	}()
+
+	resp, err := client.WaitForAcknowledgement(context.Background(), receipt, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Acknowledged != 1 || resp.AcknowledgedBy != pushovertest.UserKey {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestWaitForAcknowledgement_Expired(t *testing.T) {
+	srv, client := watchServer(t)
+	receipt := sendEmergency(t, client, "")
+	if err := srv.Expire(receipt); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.WaitForAcknowledgement(context.Background(), receipt, 0)
+	if !errors.Is(err, pushover.ErrReceiptExpired) {
+		t.Fatalf("err = %v, want ErrReceiptExpired", err)
+	}
+	if resp == nil || resp.Expired != 1 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestWaitForAcknowledgement_ContextDone(t *testing.T) {
+	_, client := watchServer(t)
+	receipt := sendEmergency(t, client, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*testPollInterval)
+	defer cancel()
+	if _, err := client.WaitForAcknowledgement(ctx, receipt, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+}